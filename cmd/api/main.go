@@ -1,14 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/auth"
 	"github.com/toor/backend/internal/config"
 	"github.com/toor/backend/internal/handlers"
-	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/middleware"
+	"github.com/toor/backend/internal/migrations"
 	"github.com/toor/backend/internal/repository"
 	"github.com/toor/backend/internal/router"
 	"github.com/toor/backend/internal/service"
@@ -22,41 +25,100 @@ func main() {
 	// 2. Conectar a la Base de Datos
 	db := storage.MustInit(cfg.DSN)
 
-	// 3. Ejecutar Migraciones
-	log.Println("Running migrations...")
-	// Se añaden todos los nuevos modelos a la migración automática
-	if err := db.AutoMigrate(
-		&models.Order{},
-		&models.SystemCounter{},
-		&models.Provider{},
-		&models.Unit{},
-		&models.Position{},
-		&models.Official{},
-	); err != nil {
-		log.Fatalf("failed to migrate database: %v", err)
+	// 3. Migraciones: "api migrate up|down|status" las administra; el
+	// servidor solo verifica que el esquema esté al día.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrations.RunCommand(db, os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
 	}
+	if err := migrations.EnsureCurrent(db); err != nil {
+		log.Fatalf("%v (run `api migrate up` first)", err)
+	}
+	if cfg.JWTSecret == "" {
+		log.Fatal("JWT_SECRET must be set")
+	}
+
+	// 4. Inyección de Dependencias (ensamblar todas las capas). Los datos
+	// iniciales se cargan sin límite de tiempo, antes de atender peticiones.
+	ctx := context.Background()
+
+	// --- Dependencias de Autenticación ---
+	userRepo := repository.NewUserRepository(db)
+	tokenManager := auth.NewTokenManager(cfg.JWTSecret, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	authService := service.NewAuthService(userRepo, tokenManager)
+	authHandler := handlers.NewAuthHandler(authService)
 
-	// 4. Inyección de Dependencias (ensamblar todas las capas)
+	// --- Dependencias de Datos Maestros (Unidades, Cargos, Funcionarios) ---
+	masterDataRepo := repository.NewMasterDataRepository(db)
+	masterDataService := service.NewMasterDataService(masterDataRepo)
+	masterDataHandler := handlers.NewMasterDataHandler(masterDataService)
 
-	// --- Dependencias de Contadores y Admin ---
+	// --- Dependencias de Contadores, Tipos de Documento y Admin ---
+	documentTypeRepo := repository.NewDocumentTypeRepository(db)
+	documentTypeService := service.NewDocumentTypeService(documentTypeRepo)
+	documentTypeHandler := handlers.NewDocumentTypeHandler(documentTypeService)
+	if err := documentTypeService.SeedDefaults(ctx); err != nil {
+		log.Fatalf("failed to seed document types: %v", err)
+	}
+	unitOfWork := repository.NewUnitOfWork(db)
 	counterRepo := repository.NewCounterRepository(db)
-	counterService := service.NewCounterService(counterRepo)
+	counterService := service.NewCounterService(counterRepo, documentTypeRepo, masterDataRepo, unitOfWork)
 	adminHandler := handlers.NewAdminHandler(counterService)
 
-	// --- Dependencias de Órdenes ---
-	orderRepo := repository.NewOrderRepository(db)
-	orderService := service.NewOrderService(orderRepo, counterService)
-	orderHandler := handlers.NewOrderHandler(orderService)
-
 	// --- Dependencias de Proveedores ---
 	providerRepo := repository.NewProviderRepository(db)
 	providerService := service.NewProviderService(providerRepo)
 	providerHandler := handlers.NewProviderHandler(providerService)
 
-	// --- Dependencias de Datos Maestros (Unidades, Cargos, Funcionarios) ---
-	masterDataRepo := repository.NewMasterDataRepository(db)
-	masterDataService := service.NewMasterDataService(masterDataRepo)
-	masterDataHandler := handlers.NewMasterDataHandler(masterDataService)
+	// --- Dependencias de Usuarios, Roles y Permisos ---
+	roleRepo := repository.NewRoleRepository(db)
+	userService := service.NewUserService(userRepo, roleRepo, masterDataRepo)
+	userHandler := handlers.NewUserHandler(userService)
+	if err := userService.SeedRoles(ctx); err != nil {
+		log.Fatalf("failed to seed roles: %v", err)
+	}
+	if err := userService.SeedAdmin(ctx, cfg.AdminUsername, cfg.AdminPassword); err != nil {
+		log.Fatalf("failed to seed admin user: %v", err)
+	}
+
+	// --- Dependencias de Alícuotas de IVA ---
+	taxRateRepo := repository.NewTaxRateRepository(db)
+	taxRateService := service.NewTaxRateService(taxRateRepo)
+	taxRateHandler := handlers.NewTaxRateHandler(taxRateService)
+	if err := taxRateService.SeedDefaults(ctx); err != nil {
+		log.Fatalf("failed to seed tax rates: %v", err)
+	}
+
+	// --- Dependencias de Tasas de Cambio ---
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateService)
+
+	// --- Dependencias de Modalidades de Contratación (UT y umbrales) ---
+	procurementRepo := repository.NewProcurementRepository(db)
+	procurementService := service.NewProcurementService(procurementRepo)
+	procurementHandler := handlers.NewProcurementHandler(procurementService)
+	if err := procurementService.SeedDefaults(ctx); err != nil {
+		log.Fatalf("failed to seed procurement thresholds: %v", err)
+	}
+
+	// --- Dependencias de Órdenes ---
+	orderRepo := repository.NewOrderRepository(db)
+	orderItemRepo := repository.NewOrderItemRepository(db)
+	quotationRepo := repository.NewQuotationRepository(db)
+	orderService := service.NewOrderService(orderRepo, orderItemRepo, quotationRepo, taxRateRepo, exchangeRateRepo, procurementRepo, providerRepo, masterDataRepo, counterService, unitOfWork)
+	orderHandler := handlers.NewOrderHandler(orderService)
+
+	// --- Dependencias de la Bitácora de Auditoría ---
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepo)
+	auditHandler := handlers.NewAuditHandler(auditService)
+
+	// --- Dependencias de Documentos PDF ---
+	documentService := service.NewDocumentService(orderRepo)
+	documentHandler := handlers.NewDocumentHandler(documentService)
 
 	// 5. Configurar y Iniciar el Router
 	ginMode := os.Getenv("GIN_MODE")
@@ -66,7 +128,7 @@ func main() {
 	gin.SetMode(ginMode)
 
 	// Se pasan todos los handlers al constructor del router
-	r := router.New(orderHandler, adminHandler, providerHandler, masterDataHandler)
+	r := router.New(middleware.RequireAuth(authService), middleware.Timeout(cfg.QueryTimeout), authHandler, orderHandler, adminHandler, providerHandler, masterDataHandler, documentHandler, taxRateHandler, exchangeRateHandler, procurementHandler, userHandler, auditHandler, documentTypeHandler)
 
 	// Leer el puerto desde el .env
 	port := os.Getenv("PORT")
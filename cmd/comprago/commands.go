@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/service"
+)
+
+// createAdmin crea un usuario con el rol ADMINISTRADOR. A diferencia del
+// arranque de la API, funciona aunque ya existan otros usuarios.
+func (a *app) createAdmin(args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	username := fs.String("username", "", "user name (required)")
+	password := fs.String("password", "", "password (required)")
+	parseFlags(fs, args)
+	if *username == "" || *password == "" {
+		return errors.New("-username and -password are required")
+	}
+
+	if err := a.users.SeedRoles(a.ctx); err != nil {
+		return err
+	}
+	roles, err := a.users.GetAllRoles(a.ctx, a.actor)
+	if err != nil {
+		return err
+	}
+	var adminRoleID uint
+	for _, role := range roles {
+		if role.Code == models.RoleAdmin {
+			adminRoleID = role.ID
+		}
+	}
+	if adminRoleID == 0 {
+		return fmt.Errorf("role %s not found", models.RoleAdmin)
+	}
+
+	user := &models.User{Username: *username, IsActive: true}
+	created, err := a.users.CreateUser(a.ctx, a.actor, user, *password, []uint{adminRoleID})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Admin user %q created (id %d)\n", created.Username, created.ID)
+	return nil
+}
+
+// closeYear cierra el ejercicio fiscal, abre el siguiente y muestra la
+// secuencia final de cada tipo de documento.
+func (a *app) closeYear(args []string) error {
+	fs := flag.NewFlagSet("close-year", flag.ExitOnError)
+	year := fs.Int("year", 0, "fiscal year to close (required)")
+	parseFlags(fs, args)
+	if *year == 0 {
+		return errors.New("-year is required")
+	}
+	// Los contadores del ejercicio nuevo se crean según los tipos configurados.
+	if err := a.docTypes.SeedDefaults(a.ctx); err != nil {
+		return err
+	}
+	closing, err := a.counters.CloseFiscalYear(a.ctx, a.actor, *year)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Fiscal year %d closed; fiscal year %d is open\n", *year, closing.NewYear)
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DOCUMENT TYPE\tUNIT\tFINAL SEQUENCE\tLAST NUMBER")
+	for _, f := range closing.FinalSequences {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", f.DocumentType, unitLabel(f.UnitID), f.LastSequence, f.LastNumber)
+	}
+	return w.Flush()
+}
+
+// listCounters muestra el último número emitido de cada contador y el
+// estado de su ejercicio fiscal.
+func (a *app) listCounters(args []string) error {
+	fs := flag.NewFlagSet("counters", flag.ExitOnError)
+	parseFlags(fs, args)
+
+	counters, err := a.counters.ListCounters(a.ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DOCUMENT TYPE\tUNIT\tYEAR\tFISCAL YEAR\tLAST SEQUENCE\tLAST NUMBER")
+	for _, c := range counters {
+		year := "-" // Serie que no se reinicia
+		if c.Year != 0 {
+			year = fmt.Sprint(c.Year)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", c.DocumentType, unitLabel(c.UnitID), year, c.FiscalYearStatus, c.LastSequence, c.LastNumber)
+	}
+	return w.Flush()
+}
+
+// providerRIFs informa los RIF de proveedores que no son válidos, los que no
+// están en la forma canónica y los proveedores que comparten un RIF. Con -fix
+// guarda normalizados los RIF válidos que no pertenecen a un duplicado.
+func (a *app) providerRIFs(args []string) error {
+	fs := flag.NewFlagSet("provider-rifs", flag.ExitOnError)
+	fix := fs.Bool("fix", false, "save the normalized RIF of valid, non-duplicated providers")
+	parseFlags(fs, args)
+
+	var report *service.RIFReport
+	var err error
+	if *fix {
+		report, err = a.providers.NormalizeRIFs(a.ctx, a.actor)
+	} else {
+		report, err = a.providers.RIFReport(a.ctx)
+	}
+	if report == nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Checked %d providers: %d invalid, %d to normalize, %d duplicated RIFs\n\n",
+		report.Checked, len(report.Invalid), len(report.ToNormalize), len(report.Duplicates))
+	if len(report.Invalid) > 0 {
+		fmt.Fprintln(w, "INVALID\tID\tNAME\tRIF\tERROR")
+		for _, i := range report.Invalid {
+			fmt.Fprintf(w, "\t%d\t%s\t%s\t%s\n", i.ProviderID, i.Name, i.RIF, i.Error)
+		}
+		fmt.Fprintln(w)
+	}
+	if len(report.ToNormalize) > 0 {
+		fmt.Fprintln(w, "TO NORMALIZE\tID\tNAME\tRIF\tNORMALIZED\tSAVED")
+		for _, i := range report.ToNormalize {
+			fmt.Fprintf(w, "\t%d\t%s\t%s\t%s\t%t\n", i.ProviderID, i.Name, i.RIF, i.Normalized, i.Applied)
+		}
+		fmt.Fprintln(w)
+	}
+	if len(report.Duplicates) > 0 {
+		fmt.Fprintln(w, "DUPLICATED\tID\tNAME\tRIF")
+		for _, d := range report.Duplicates {
+			fmt.Fprintf(w, "%s\t\t\t\n", d.RIF)
+			for _, p := range d.Providers {
+				fmt.Fprintf(w, "\t%d\t%s\t%s\n", p.ProviderID, p.Name, p.RIF)
+			}
+		}
+	}
+	if flushErr := w.Flush(); err == nil {
+		err = flushErr
+	}
+	return err
+}
+
+// unitLabel muestra la unidad de una serie; las series globales no tienen.
+func unitLabel(unitID uint) string {
+	if unitID == 0 {
+		return "-"
+	}
+	return fmt.Sprint(unitID)
+}
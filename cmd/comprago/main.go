@@ -0,0 +1,138 @@
+// Comando comprago: herramientas de administración para operadores. Usa los
+// mismos repositorios y servicios que la API, de modo que las validaciones,
+// los permisos y la bitácora de auditoría son idénticos.
+//
+// Uso:
+//
+//	comprago migrate up|down|status
+//	comprago create-admin -username USUARIO -password CLAVE
+//	comprago seed [-units f.csv] [-positions f.csv] [-officials f.csv] [-providers f.csv]
+//	comprago close-year -year AÑO
+//	comprago counters
+//	comprago provider-rifs [-fix]
+//	comprago export -file datos.json
+//	comprago import -file datos.json
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/toor/backend/internal/config"
+	"github.com/toor/backend/internal/migrations"
+	"github.com/toor/backend/internal/repository"
+	"github.com/toor/backend/internal/service"
+	"github.com/toor/backend/internal/storage"
+	"gorm.io/gorm"
+)
+
+const usage = `Usage: comprago <command> [flags]
+
+Commands:
+  migrate up|down|status   Apply, revert or list schema migrations
+  create-admin             Create a user with the ADMINISTRADOR role
+  seed                     Load units, positions, officials and providers from CSV files
+  close-year               Close a fiscal year and open the next one
+  counters                 List the document counters
+  provider-rifs            Report invalid, non-normalized and duplicate provider RIFs
+  export                   Export master data, providers and exchange rates to JSON
+  import                   Import a file produced by export
+
+Run "comprago <command> -h" for the flags of each command.
+`
+
+// app reúne los servicios que usan los comandos. ctx se cancela con Ctrl+C,
+// lo que interrumpe la consulta en curso.
+type app struct {
+	ctx          context.Context
+	db           *gorm.DB
+	actor        *service.Actor
+	users        service.UserService
+	masterData   service.MasterDataService
+	providers    service.ProviderService
+	exchangeRate service.ExchangeRateService
+	counters     service.CounterService
+	docTypes     service.DocumentTypeService
+}
+
+func newApp(ctx context.Context, db *gorm.DB) *app {
+	masterDataRepo := repository.NewMasterDataRepository(db)
+	docTypeRepo := repository.NewDocumentTypeRepository(db)
+	return &app{
+		ctx:          ctx,
+		db:           db,
+		actor:        service.SystemActor("comprago"),
+		users:        service.NewUserService(repository.NewUserRepository(db), repository.NewRoleRepository(db), masterDataRepo),
+		masterData:   service.NewMasterDataService(masterDataRepo),
+		providers:    service.NewProviderService(repository.NewProviderRepository(db)),
+		exchangeRate: service.NewExchangeRateService(repository.NewExchangeRateRepository(db)),
+		counters:     service.NewCounterService(repository.NewCounterRepository(db), docTypeRepo, masterDataRepo, repository.NewUnitOfWork(db)),
+		docTypes:     service.NewDocumentTypeService(docTypeRepo),
+	}
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command, args := os.Args[1], os.Args[2:]
+	if command == "-h" || command == "--help" || command == "help" {
+		fmt.Print(usage)
+		return
+	}
+
+	cfg := config.Load()
+	db := storage.MustInit(cfg.DSN)
+
+	if command == "migrate" {
+		if err := migrations.RunCommand(db, args, os.Stdout); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
+	}
+	// El resto de los comandos trabaja sobre el esquema actual.
+	if err := migrations.EnsureCurrent(db); err != nil {
+		log.Fatalf("%v (run `comprago migrate up` first)", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	a := newApp(ctx, db)
+	var err error
+	switch command {
+	case "create-admin":
+		err = a.createAdmin(args)
+	case "seed":
+		err = a.seed(args)
+	case "close-year":
+		err = a.closeYear(args)
+	case "counters":
+		err = a.listCounters(args)
+	case "provider-rifs":
+		err = a.providerRIFs(args)
+	case "export":
+		err = a.exportData(args)
+	case "import":
+		err = a.importData(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("%s: %v", command, err)
+	}
+}
+
+// parseFlags analiza las banderas de un comando y sale con la ayuda si hay error.
+func parseFlags(fs *flag.FlagSet, args []string) {
+	fs.SetOutput(os.Stderr)
+	if err := fs.Parse(args); err != nil {
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/rif"
+)
+
+// dataFileVersion identifica el formato del archivo de export/import.
+const dataFileVersion = 1
+
+// dataFile es el contenido de un archivo de export. Los funcionarios hacen
+// referencia a su unidad y cargo por nombre para poder importarse en otra
+// base de datos con otros IDs.
+type dataFile struct {
+	Version       int                   `json:"version"`
+	ExportedAt    time.Time             `json:"exportedAt"`
+	Units         []models.Unit         `json:"units"`
+	Positions     []models.Position     `json:"positions"`
+	Officials     []officialRecord      `json:"officials"`
+	Providers     []models.Provider     `json:"providers"`
+	ExchangeRates []models.ExchangeRate `json:"exchangeRates"`
+}
+
+type officialRecord struct {
+	FullName string `json:"fullName"`
+	Unit     string `json:"unit"`
+	Position string `json:"position"`
+	IsActive bool   `json:"isActive"`
+}
+
+// importSummary cuenta los registros creados y los que ya existían.
+type importSummary struct {
+	created, skipped map[string]int
+}
+
+func newImportSummary() *importSummary {
+	return &importSummary{created: map[string]int{}, skipped: map[string]int{}}
+}
+
+func (s *importSummary) print() {
+	for _, kind := range []string{"units", "positions", "officials", "providers", "exchange rates"} {
+		if s.created[kind]+s.skipped[kind] > 0 {
+			fmt.Printf("%-15s %d created, %d already existed\n", kind+":", s.created[kind], s.skipped[kind])
+		}
+	}
+}
+
+func (a *app) exportData(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	file := fs.String("file", "", "output file (default: standard output)")
+	parseFlags(fs, args)
+
+	data := dataFile{Version: dataFileVersion, ExportedAt: time.Now()}
+	var err error
+	if data.Units, err = a.masterData.GetAllUnits(a.ctx); err != nil {
+		return err
+	}
+	if data.Positions, err = a.masterData.GetAllPositions(a.ctx); err != nil {
+		return err
+	}
+	officials, err := a.masterData.GetAllOfficials(a.ctx)
+	if err != nil {
+		return err
+	}
+	for _, o := range officials {
+		data.Officials = append(data.Officials, officialRecord{
+			FullName: o.FullName, Unit: o.Unit.Name, Position: o.Position.Name, IsActive: o.IsActive,
+		})
+	}
+	if data.Providers, err = a.providers.GetAllProviders(a.ctx); err != nil {
+		return err
+	}
+	if data.ExchangeRates, err = a.exchangeRate.GetAllExchangeRates(a.ctx, ""); err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func (a *app) importData(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "file produced by export (required)")
+	parseFlags(fs, args)
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	var data dataFile
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("invalid file: %w", err)
+	}
+	if data.Version != dataFileVersion {
+		return fmt.Errorf("unsupported file version %d", data.Version)
+	}
+	return a.load(&data)
+}
+
+// seed carga datos maestros y proveedores desde archivos CSV con encabezado:
+//
+//	units:     name
+//	positions: name
+//	officials: fullName,unit,position
+//	providers: name,rif,address
+func (a *app) seed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	unitsFile := fs.String("units", "", "CSV file with units")
+	positionsFile := fs.String("positions", "", "CSV file with positions")
+	officialsFile := fs.String("officials", "", "CSV file with officials")
+	providersFile := fs.String("providers", "", "CSV file with providers")
+	parseFlags(fs, args)
+	if *unitsFile == "" && *positionsFile == "" && *officialsFile == "" && *providersFile == "" {
+		return errors.New("at least one of -units, -positions, -officials or -providers is required")
+	}
+
+	var data dataFile
+	err := readCSV(*unitsFile, []string{"name"}, func(row map[string]string) {
+		data.Units = append(data.Units, models.Unit{Name: row["name"], IsActive: true})
+	})
+	if err != nil {
+		return err
+	}
+	err = readCSV(*positionsFile, []string{"name"}, func(row map[string]string) {
+		data.Positions = append(data.Positions, models.Position{Name: row["name"], IsActive: true})
+	})
+	if err != nil {
+		return err
+	}
+	err = readCSV(*officialsFile, []string{"fullname", "unit", "position"}, func(row map[string]string) {
+		data.Officials = append(data.Officials, officialRecord{
+			FullName: row["fullname"], Unit: row["unit"], Position: row["position"], IsActive: true,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	err = readCSV(*providersFile, []string{"name"}, func(row map[string]string) {
+		data.Providers = append(data.Providers, models.Provider{Name: row["name"], RIF: row["rif"], Address: row["address"]})
+	})
+	if err != nil {
+		return err
+	}
+	return a.load(&data)
+}
+
+// load crea los registros que todavía no existen, comparando por nombre (o
+// RIF en los proveedores), de modo que puede ejecutarse varias veces.
+func (a *app) load(data *dataFile) error {
+	summary := newImportSummary()
+	defer summary.print()
+
+	units, err := a.masterData.GetAllUnits(a.ctx)
+	if err != nil {
+		return err
+	}
+	unitIDs := map[string]uint{}
+	for _, u := range units {
+		unitIDs[normalizeKey(u.Name)] = u.ID
+	}
+	for _, u := range data.Units {
+		if _, ok := unitIDs[normalizeKey(u.Name)]; ok {
+			summary.skipped["units"]++
+			continue
+		}
+		unit := &models.Unit{Name: strings.TrimSpace(u.Name), Code: strings.TrimSpace(u.Code), IsActive: u.IsActive}
+		if _, err := a.masterData.CreateUnit(a.ctx, a.actor, unit); err != nil {
+			return fmt.Errorf("unit %q: %w", u.Name, err)
+		}
+		unitIDs[normalizeKey(unit.Name)] = unit.ID
+		summary.created["units"]++
+	}
+
+	positions, err := a.masterData.GetAllPositions(a.ctx)
+	if err != nil {
+		return err
+	}
+	positionIDs := map[string]uint{}
+	for _, p := range positions {
+		positionIDs[normalizeKey(p.Name)] = p.ID
+	}
+	for _, p := range data.Positions {
+		if _, ok := positionIDs[normalizeKey(p.Name)]; ok {
+			summary.skipped["positions"]++
+			continue
+		}
+		position := &models.Position{Name: strings.TrimSpace(p.Name), IsActive: p.IsActive}
+		if _, err := a.masterData.CreatePosition(a.ctx, a.actor, position); err != nil {
+			return fmt.Errorf("position %q: %w", p.Name, err)
+		}
+		positionIDs[normalizeKey(position.Name)] = position.ID
+		summary.created["positions"]++
+	}
+
+	officials, err := a.masterData.GetAllOfficials(a.ctx)
+	if err != nil {
+		return err
+	}
+	existingOfficials := map[string]bool{}
+	for _, o := range officials {
+		existingOfficials[normalizeKey(o.FullName)] = true
+	}
+	for _, o := range data.Officials {
+		if existingOfficials[normalizeKey(o.FullName)] {
+			summary.skipped["officials"]++
+			continue
+		}
+		unitID, ok := unitIDs[normalizeKey(o.Unit)]
+		if !ok {
+			return fmt.Errorf("official %q: unit %q not found", o.FullName, o.Unit)
+		}
+		positionID, ok := positionIDs[normalizeKey(o.Position)]
+		if !ok {
+			return fmt.Errorf("official %q: position %q not found", o.FullName, o.Position)
+		}
+		official := &models.Official{
+			FullName: strings.TrimSpace(o.FullName), IsActive: o.IsActive, UnitID: unitID, PositionID: positionID,
+		}
+		if _, err := a.masterData.CreateOfficial(a.ctx, a.actor, official); err != nil {
+			return fmt.Errorf("official %q: %w", o.FullName, err)
+		}
+		existingOfficials[normalizeKey(official.FullName)] = true
+		summary.created["officials"]++
+	}
+
+	providers, err := a.providers.GetAllProviders(a.ctx)
+	if err != nil {
+		return err
+	}
+	existingProviders := map[string]bool{}
+	for _, p := range providers {
+		existingProviders[providerKey(p)] = true
+	}
+	for _, p := range data.Providers {
+		if existingProviders[providerKey(p)] {
+			summary.skipped["providers"]++
+			continue
+		}
+		provider := &models.Provider{Name: strings.TrimSpace(p.Name), RIF: strings.TrimSpace(p.RIF), Address: p.Address}
+		if _, err := a.providers.CreateProvider(a.ctx, a.actor, provider); err != nil {
+			return fmt.Errorf("provider %q: %w", p.Name, err)
+		}
+		existingProviders[providerKey(*provider)] = true
+		summary.created["providers"]++
+	}
+
+	if len(data.ExchangeRates) > 0 {
+		// Se reutiliza la carga por CSV, que actualiza las tasas ya registradas.
+		saved, err := a.exchangeRate.ImportCSV(a.ctx, a.actor, exchangeRatesCSV(data.ExchangeRates))
+		if err != nil {
+			return fmt.Errorf("exchange rates: %w", err)
+		}
+		summary.created["exchange rates"] = len(saved)
+	}
+	return nil
+}
+
+// readCSV lee un archivo CSV con encabezado y llama a fn por cada fila, con
+// las columnas en minúsculas. Un nombre de archivo vacío no hace nada.
+func readCSV(file string, required []string, fn func(row map[string]string)) error {
+	if file == "" {
+		return nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%s: could not read the header: %w", file, err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	for _, column := range required {
+		found := false
+		for _, name := range header {
+			found = found || name == column
+		}
+		if !found {
+			return fmt.Errorf("%s: missing column %q", file, column)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		row := map[string]string{}
+		for i, name := range header {
+			if i < len(record) {
+				row[name] = strings.TrimSpace(record[i])
+			}
+		}
+		for _, column := range required {
+			if row[column] == "" {
+				return fmt.Errorf("%s:%d: column %q is empty", file, line, column)
+			}
+		}
+		fn(row)
+	}
+}
+
+func exchangeRatesCSV(rates []models.ExchangeRate) io.Reader {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"date", "currency", "rate", "source"})
+	for _, r := range rates {
+		w.Write([]string{r.Date.Format("2006-01-02"), r.Currency, r.Rate.String(), r.Source})
+	}
+	w.Flush()
+	return &buf
+}
+
+func normalizeKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// providerKey identifica al proveedor por su RIF normalizado, o por su nombre
+// si no lo tiene.
+func providerKey(p models.Provider) string {
+	if value := strings.TrimSpace(p.RIF); value != "" {
+		if normalized, err := rif.Normalize(value); err == nil {
+			return "rif:" + normalized
+		}
+		return "rif:" + strings.ToUpper(value)
+	}
+	return "name:" + normalizeKey(p.Name)
+}
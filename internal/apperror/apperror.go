@@ -0,0 +1,148 @@
+// Package apperror define los errores de dominio que devuelven los servicios
+// y su traducción a respuestas HTTP. Cada error tiene un tipo (Kind), que
+// determina el código de estado, un código estable que el cliente puede usar
+// para distinguir los casos y un mensaje en español para mostrar al usuario.
+//
+// Los errores se declaran como variables del paquete que los produce y se
+// reconocen con errors.Is, igual que cualquier otro error:
+//
+//	var ErrOrderLocked = apperror.Conflict("ORDER_LOCKED", "la orden ya no puede modificarse")
+//	return fmt.Errorf("%w (%s)", ErrOrderLocked, order.Status)
+package apperror
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// Kind clasifica el error y determina el código HTTP de la respuesta.
+type Kind string
+
+const (
+	KindValidation    Kind = "VALIDATION"    // 400: datos de entrada inválidos
+	KindUnauthorized  Kind = "UNAUTHORIZED"  // 401: falta autenticación o no es válida
+	KindForbidden     Kind = "FORBIDDEN"     // 403: el usuario no tiene permiso
+	KindNotFound      Kind = "NOT_FOUND"     // 404: el registro no existe
+	KindConflict      Kind = "CONFLICT"      // 409: choca con el estado actual de los datos
+	KindInUse         Kind = "IN_USE"        // 409: otros registros lo referencian
+	KindGone          Kind = "GONE"          // 410: el recurso o la ruta se retiró
+	KindUnprocessable Kind = "UNPROCESSABLE" // 422: falta configuración para completar la operación
+	KindTimeout       Kind = "TIMEOUT"       // 504: la operación no terminó a tiempo
+	KindInternal      Kind = "INTERNAL"      // 500: error inesperado
+)
+
+// Status devuelve el código HTTP que corresponde al tipo de error.
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict, KindInUse:
+		return http.StatusConflict
+	case KindGone:
+		return http.StatusGone
+	case KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error es un error de dominio.
+type Error struct {
+	Kind Kind
+	// Code identifica el caso de forma estable, ej. "ORDER_LOCKED".
+	Code string
+	// Message es el texto para el usuario, en español.
+	Message string
+	// Details son datos adicionales para el cliente (campos inválidos,
+	// registro en conflicto, etc.); nil si no hay.
+	Details any
+	// Err es la causa, que no se muestra al cliente.
+	Err error
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func Validation(code, message string) *Error    { return New(KindValidation, code, message) }
+func Unauthorized(code, message string) *Error  { return New(KindUnauthorized, code, message) }
+func Forbidden(code, message string) *Error     { return New(KindForbidden, code, message) }
+func NotFound(code, message string) *Error      { return New(KindNotFound, code, message) }
+func Conflict(code, message string) *Error      { return New(KindConflict, code, message) }
+func InUse(code, message string) *Error         { return New(KindInUse, code, message) }
+func Gone(code, message string) *Error          { return New(KindGone, code, message) }
+func Unprocessable(code, message string) *Error { return New(KindUnprocessable, code, message) }
+
+func (e *Error) Error() string { return e.Message }
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is considera iguales los errores con el mismo código, de modo que las
+// copias creadas con WithMessage, WithDetails o Wrap siguen reconociéndose
+// con errors.Is(err, ErrX).
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithMessage devuelve una copia del error con otro mensaje.
+func (e *Error) WithMessage(message string) *Error {
+	c := *e
+	c.Message = message
+	return &c
+}
+
+// WithDetails devuelve una copia del error con los detalles indicados.
+func (e *Error) WithDetails(details any) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// Wrap devuelve una copia del error con la causa indicada.
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+var (
+	// ErrNotFound se usa cuando un registro no existe y no hay un error más específico.
+	ErrNotFound = NotFound("NOT_FOUND", "el registro no existe")
+	// ErrTimeout se usa cuando vence el tiempo de la petición.
+	ErrTimeout = New(KindTimeout, "TIMEOUT", "la operación tardó demasiado; intente de nuevo")
+	// ErrInternal oculta al cliente los errores inesperados.
+	ErrInternal = New(KindInternal, "INTERNAL_ERROR", "ocurrió un error inesperado")
+)
+
+// From convierte cualquier error en un error de dominio. Si err contiene un
+// *Error se conserva el mensaje completo de err, que incluye el detalle
+// agregado con fmt.Errorf("%w: ..."); los registros inexistentes y los
+// vencimientos de tiempo tienen su propio error; el resto es ErrInternal
+// con err como causa.
+func From(err error) *Error {
+	var appErr *Error
+	switch {
+	case errors.As(err, &appErr):
+		c := *appErr
+		c.Message = err.Error()
+		return &c
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound.Wrap(err)
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return ErrTimeout.Wrap(err)
+	default:
+		return ErrInternal.Wrap(err)
+	}
+}
@@ -0,0 +1,328 @@
+// Package audit registra en la bitácora (models.AuditEntry) cada alta,
+// modificación y baja de las entidades auditadas. Funciona con callbacks de
+// GORM, así que cubre cualquier escritura que pase por la base de datos; quién
+// hizo el cambio se toma del contexto de la consulta (ver WithActor).
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/toor/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Actor identifica a quien realiza el cambio.
+type Actor struct {
+	UserID   *uint
+	Username string
+	IP       string
+}
+
+type actorKey struct{}
+
+// WithActor devuelve un contexto que lleva al actor hasta los callbacks.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+type reasonKey struct{}
+
+// WithReason devuelve un contexto cuyas entradas de bitácora registran la
+// justificación del cambio (por ejemplo, un ajuste manual de un contador).
+func WithReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, reasonKey{}, reason)
+}
+
+func reasonFrom(ctx context.Context) string {
+	if ctx != nil {
+		if reason, ok := ctx.Value(reasonKey{}).(string); ok {
+			return reason
+		}
+	}
+	return ""
+}
+
+// actorFrom obtiene el actor del contexto; sin actor, el cambio se atribuye al sistema.
+func actorFrom(ctx context.Context) Actor {
+	if ctx != nil {
+		if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+			return actor
+		}
+	}
+	return Actor{Username: "sistema"}
+}
+
+// auditedModels son las entidades cuyos cambios se registran.
+var auditedModels = []interface{}{
+	&models.Order{},
+	&models.Provider{},
+	&models.Unit{},
+	&models.Position{},
+	&models.Official{},
+	&models.SystemCounter{},
+	&models.FiscalYear{},
+	&models.DocumentType{},
+}
+
+// ignoredColumns no se consideran un cambio por sí solas.
+var ignoredColumns = map[string]bool{"updated_at": true}
+
+const beforeKey = "audit:before"
+
+// Register instala los callbacks de auditoría en db.
+func Register(db *gorm.DB) error {
+	a := &auditor{tables: map[string]bool{}}
+	for _, model := range auditedModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("audit: %w", err)
+		}
+		a.tables[stmt.Schema.Table] = true
+	}
+
+	// Todos los callbacks quedan dentro de la transacción de la sentencia.
+	const begin, commit = "gorm:begin_transaction", "gorm:commit_or_rollback_transaction"
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Before(commit).Register("audit:after_create", a.afterCreate); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After(begin).Before("gorm:update").Register("audit:before_update", a.loadBefore); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Before(commit).Register("audit:after_update", a.afterUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After(begin).Before("gorm:delete").Register("audit:before_delete", a.loadBefore); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Before(commit).Register("audit:after_delete", a.afterDelete)
+}
+
+type auditor struct {
+	tables map[string]bool
+}
+
+func (a *auditor) audited(db *gorm.DB) bool {
+	s := db.Statement.Schema
+	return s != nil && a.tables[s.Table] && s.PrioritizedPrimaryField != nil
+}
+
+func (a *auditor) afterCreate(db *gorm.DB) {
+	// RowsAffected es 0 cuando un ON CONFLICT DO NOTHING no insertó nada.
+	if db.Error != nil || !a.audited(db) || db.Statement.RowsAffected == 0 {
+		return
+	}
+	var entries []models.AuditEntry
+	eachModel(db.Statement.ReflectValue, func(value reflect.Value) {
+		row := rowFromModel(db, value)
+		entries = append(entries, a.entry(db, models.AuditCreate, row, nil, row))
+	})
+	a.save(db, entries)
+}
+
+// loadBefore lee las filas que la sentencia va a modificar o eliminar.
+func (a *auditor) loadBefore(db *gorm.DB) {
+	if db.Error != nil || !a.audited(db) {
+		return
+	}
+	query, ok := a.affectedRows(db)
+	if !ok {
+		return
+	}
+	rows, err := a.findRows(db, query)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: %w", err))
+		return
+	}
+	db.InstanceSet(beforeKey, rows)
+}
+
+func (a *auditor) afterUpdate(db *gorm.DB) {
+	if db.Error != nil || !a.audited(db) || db.Statement.RowsAffected == 0 {
+		return
+	}
+	before := beforeRows(db)
+	if len(before) == 0 {
+		return
+	}
+
+	pk := db.Statement.Schema.PrioritizedPrimaryField.DBName
+	ids := make([]interface{}, 0, len(before))
+	for _, row := range before {
+		ids = append(ids, row[pk])
+	}
+	query := a.newQuery(db).Unscoped().Where(clause.IN{Column: clause.Column{Name: pk}, Values: ids})
+	after, err := a.findRows(db, query)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: %w", err))
+		return
+	}
+	afterByID := map[string]map[string]interface{}{}
+	for _, row := range after {
+		afterByID[fmt.Sprint(row[pk])] = row
+	}
+
+	var entries []models.AuditEntry
+	for _, old := range before {
+		current, ok := afterByID[fmt.Sprint(old[pk])]
+		if !ok {
+			continue
+		}
+		oldValues, newValues := diff(old, current)
+		if len(newValues) == 0 {
+			continue
+		}
+		entries = append(entries, a.entry(db, models.AuditUpdate, old, oldValues, newValues))
+	}
+	a.save(db, entries)
+}
+
+func (a *auditor) afterDelete(db *gorm.DB) {
+	if db.Error != nil || !a.audited(db) || db.Statement.RowsAffected == 0 {
+		return
+	}
+	var entries []models.AuditEntry
+	for _, row := range beforeRows(db) {
+		entries = append(entries, a.entry(db, models.AuditDelete, row, row, nil))
+	}
+	a.save(db, entries)
+}
+
+// affectedRows arma una consulta con las mismas condiciones de la sentencia:
+// la clave primaria del modelo (si viene cargada) y el WHERE explícito. Si no
+// hay ninguna condición no se audita, porque GORM rechaza esas sentencias.
+func (a *auditor) affectedRows(db *gorm.DB) (*gorm.DB, bool) {
+	stmt := db.Statement
+	query := a.newQuery(db)
+	conditions := false
+
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			query = query.Clauses(where)
+			conditions = true
+		}
+	}
+	if stmt.ReflectValue.Kind() == reflect.Struct {
+		for _, field := range stmt.Schema.PrimaryFields {
+			if value, zero := field.ValueOf(stmt.Context, stmt.ReflectValue); !zero {
+				query = query.Where(clause.Eq{Column: clause.Column{Name: field.DBName}, Value: value})
+				conditions = true
+			}
+		}
+	}
+	return query, conditions
+}
+
+// newQuery abre una consulta sobre la tabla de la sentencia, en la misma
+// transacción y con el mismo contexto, sin disparar otros callbacks.
+func (a *auditor) newQuery(db *gorm.DB) *gorm.DB {
+	model := reflect.New(db.Statement.Schema.ModelType).Interface()
+	return db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Model(model)
+}
+
+// findRows ejecuta la consulta sobre el tipo del modelo (así cada columna se
+// lee con su tipo Go, por ejemplo money.Money) y devuelve las filas como mapas.
+func (a *auditor) findRows(db *gorm.DB, query *gorm.DB) ([]map[string]interface{}, error) {
+	dest := reflect.New(reflect.SliceOf(db.Statement.Schema.ModelType))
+	if err := query.Find(dest.Interface()).Error; err != nil {
+		return nil, err
+	}
+	var rows []map[string]interface{}
+	eachModel(dest.Elem(), func(value reflect.Value) {
+		rows = append(rows, rowFromModel(db, value))
+	})
+	return rows, nil
+}
+
+func (a *auditor) entry(db *gorm.DB, action string, row, before, after map[string]interface{}) models.AuditEntry {
+	actor := actorFrom(db.Statement.Context)
+	s := db.Statement.Schema
+	id, _ := row[s.PrioritizedPrimaryField.DBName].(uint)
+	return models.AuditEntry{
+		UserID:     actor.UserID,
+		Username:   actor.Username,
+		IP:         actor.IP,
+		EntityType: s.Name,
+		EntityID:   id,
+		Action:     action,
+		Reason:     reasonFrom(db.Statement.Context),
+		Before:     marshal(before),
+		After:      marshal(after),
+	}
+}
+
+// save guarda las entradas en la misma transacción que el cambio, de modo que
+// si la bitácora falla el cambio tampoco se aplica.
+func (a *auditor) save(db *gorm.DB, entries []models.AuditEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	if err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Create(&entries).Error; err != nil {
+		db.AddError(fmt.Errorf("audit: %w", err))
+	}
+}
+
+func beforeRows(db *gorm.DB) []map[string]interface{} {
+	value, ok := db.InstanceGet(beforeKey)
+	if !ok {
+		return nil
+	}
+	rows, _ := value.([]map[string]interface{})
+	return rows
+}
+
+// rowFromModel convierte un modelo recién creado en un mapa columna → valor.
+func rowFromModel(db *gorm.DB, value reflect.Value) map[string]interface{} {
+	row := map[string]interface{}{}
+	for _, field := range db.Statement.Schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		v, _ := field.ValueOf(db.Statement.Context, value)
+		row[field.DBName] = v
+	}
+	return row
+}
+
+func eachModel(value reflect.Value, fn func(reflect.Value)) {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			fn(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		fn(value)
+	}
+}
+
+// diff devuelve los valores anteriores y nuevos de las columnas que cambiaron.
+// Se comparan en JSON para no depender del tipo con el que el driver leyó cada columna.
+func diff(before, after map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	oldValues := map[string]interface{}{}
+	newValues := map[string]interface{}{}
+	for column, value := range after {
+		if ignoredColumns[column] {
+			continue
+		}
+		if string(marshal(before[column])) != string(marshal(value)) {
+			oldValues[column] = before[column]
+			newValues[column] = value
+		}
+	}
+	return oldValues, newValues
+}
+
+func marshal(v interface{}) models.JSON {
+	if m, ok := v.(map[string]interface{}); ok && m == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return models.JSON(fmt.Sprintf("%q", fmt.Sprint(v)))
+	}
+	return data
+}
@@ -0,0 +1,113 @@
+// Package auth emite y valida los tokens JWT de acceso y de refresh.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/toor/backend/internal/apperror"
+)
+
+// Tipos de token. Un token de refresh no sirve para acceder a la API ni al revés.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// ErrInvalidToken se devuelve cuando el token no es válido, venció o no es del tipo esperado.
+var ErrInvalidToken = apperror.Unauthorized("INVALID_TOKEN", "token inválido o vencido")
+
+// Claims son los datos que viajan en el token.
+type Claims struct {
+	UserID    uint   `json:"uid"`
+	Username  string `json:"username"`
+	TokenType string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair es la respuesta de login y refresh.
+type TokenPair struct {
+	AccessToken      string    `json:"accessToken"`
+	RefreshToken     string    `json:"refreshToken"`
+	TokenType        string    `json:"tokenType"` // Siempre "Bearer"
+	AccessExpiresAt  time.Time `json:"accessExpiresAt"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+}
+
+// TokenManager firma los tokens con HMAC-SHA256.
+type TokenManager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewTokenManager(secret string, accessTTL, refreshTTL time.Duration) *TokenManager {
+	return &TokenManager{secret: []byte(secret), accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+// Issue genera un par de tokens nuevo para el usuario.
+func (m *TokenManager) Issue(userID uint, username string) (*TokenPair, error) {
+	now := time.Now()
+	access, accessExp, err := m.sign(userID, username, TokenTypeAccess, now, m.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, refreshExp, err := m.sign(userID, username, TokenTypeRefresh, now, m.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		TokenType:        "Bearer",
+		AccessExpiresAt:  accessExp,
+		RefreshExpiresAt: refreshExp,
+	}, nil
+}
+
+// Parse valida la firma, el vencimiento y el tipo del token.
+func (m *TokenManager) Parse(token, tokenType string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.TokenType != tokenType || claims.ID == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (m *TokenManager) sign(userID uint, username, tokenType string, now time.Time, ttl time.Duration) (string, time.Time, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := now.Add(ttl)
+	claims := Claims{
+		UserID:    userID,
+		Username:  username,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   username,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	return signed, expiresAt, err
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -3,12 +3,24 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	DSN string
+
+	// Autenticación
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// Usuario administrador que se crea si no existe ningún usuario.
+	AdminUsername string
+	AdminPassword string
+
+	// Tiempo máximo de las consultas de cada petición (0 = sin límite).
+	QueryTimeout time.Duration
 }
 
 func Load() *Config {
@@ -18,5 +30,27 @@ func Load() *Config {
 
 	return &Config{
 		DSN: os.Getenv("DSN"),
+
+		JWTSecret:       os.Getenv("JWT_SECRET"),
+		AccessTokenTTL:  durationEnv("JWT_ACCESS_TTL", 15*time.Minute),
+		RefreshTokenTTL: durationEnv("JWT_REFRESH_TTL", 7*24*time.Hour),
+		AdminUsername:   os.Getenv("ADMIN_USERNAME"),
+		AdminPassword:   os.Getenv("ADMIN_PASSWORD"),
+
+		QueryTimeout: durationEnv("DB_QUERY_TIMEOUT", 30*time.Second),
 	}
-}
\ No newline at end of file
+}
+
+// durationEnv lee una duración en formato de Go (ej. "15m", "168h").
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid %s=%q, using %s", key, value, fallback)
+		return fallback
+	}
+	return d
+}
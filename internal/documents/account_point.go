@@ -0,0 +1,52 @@
+package documents
+
+import (
+	"fmt"
+
+	"github.com/toor/backend/internal/models"
+)
+
+// renderAccountPoint genera el punto de cuenta (paso 3) que se somete a aprobación.
+func renderAccountPoint(d *document, order *models.Order) {
+	name, position := officialSignature(order)
+
+	d.title("PUNTO DE CUENTA")
+	d.field("Número", order.AccountPointNumber)
+	d.field("Memo de Referencia", order.MemoNumber)
+	d.field("Fecha", formatDate(order.AccountPointDate))
+	d.field("Presentado por", name)
+	d.field("Unidad Solicitante", order.RequestingUnit)
+	d.field("Asunto", order.Subject)
+	d.field("Modalidad", order.PriceInquiryType)
+	if !order.AmountUT.IsZero() {
+		d.field("Monto en U.T.", formatQuantity(order.AmountUT.Round(2)))
+	}
+	d.field("Categoría Programática", order.ProgrammaticCategory)
+	d.field("UEL", order.UEL)
+
+	d.section("Síntesis")
+	d.paragraph(order.Synthesis)
+
+	d.section("Oferta Recomendada")
+	d.field("Proveedor", order.Provider)
+	d.field("Presupuesto N°", order.BudgetNumber)
+	d.field("Fecha del Presupuesto", formatDate(order.BudgetDate))
+	d.field("Tiempo de Entrega", order.DeliveryTime)
+	d.field("Calidad de la Oferta", order.OfferQuality)
+	d.field("Monto Base", formatAmount(order.BaseAmount))
+	d.field("IVA", formatAmount(order.IvaAmount))
+	d.field("Monto Total", formatAmount(order.TotalAmount))
+	if order.Currency != "" && order.Currency != models.CurrencyVES {
+		d.field("Moneda", order.Currency)
+		d.field("Tasa de Cambio", fmt.Sprintf("Bs. %s por %s (%s)",
+			formatRate(order.ExchangeRate), order.Currency, formatDatePtr(order.ExchangeRateDate)))
+		d.field("Monto Base (Bs.)", formatAmount(order.BaseAmountVES))
+		d.field("IVA (Bs.)", formatAmount(order.IvaAmountVES))
+		d.field("Monto Total (Bs.)", formatAmount(order.TotalAmountVES))
+	}
+
+	d.section("Decisión")
+	d.paragraph("Aprobado: ______     Negado: ______     Diferido: ______")
+
+	d.signature(name, position)
+}
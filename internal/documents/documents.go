@@ -0,0 +1,252 @@
+// Package documents genera en PDF los documentos oficiales del flujo de
+// compras (memo, punto de cuenta y orden de compra/servicio) a partir de los
+// datos de una orden. Se usa una librería en Go puro para que funcione en la
+// imagen Alpine sin dependencias de sistema.
+package documents
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/money"
+)
+
+// Kind identifica el documento a generar.
+type Kind string
+
+const (
+	KindMemo          Kind = "memo"
+	KindAccountPoint  Kind = "account-point"
+	KindPurchaseOrder Kind = "order"
+)
+
+// ErrUnknownKind se devuelve cuando se solicita un documento que no existe.
+var ErrUnknownKind = apperror.NotFound("UNKNOWN_DOCUMENT", "tipo de documento desconocido")
+
+// ParseKind convierte el nombre recibido en la URL (ej. "memo.pdf") en un Kind.
+func ParseKind(name string) (Kind, error) {
+	kind := Kind(strings.TrimSuffix(name, ".pdf"))
+	switch kind {
+	case KindMemo, KindAccountPoint, KindPurchaseOrder:
+		return kind, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownKind, name)
+}
+
+// Render genera el PDF solicitado para la orden. La orden debe venir con el
+// funcionario (y su cargo), la unidad, el proveedor y los ítems precargados.
+func Render(kind Kind, order *models.Order) ([]byte, error) {
+	doc := newDocument()
+	switch kind {
+	case KindMemo:
+		renderMemo(doc, order)
+	case KindAccountPoint:
+		renderAccountPoint(doc, order)
+	case KindPurchaseOrder:
+		renderPurchaseOrder(doc, order)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// FileName devuelve el nombre sugerido para descargar el documento: el número
+// del propio documento o, si todavía no lo tiene, el del memo.
+func FileName(kind Kind, order *models.Order) string {
+	ref := order.MemoNumber
+	switch {
+	case kind == KindAccountPoint && order.AccountPointNumber != "":
+		ref = order.AccountPointNumber
+	case kind == KindPurchaseOrder && order.PurchaseOrderNumber != "":
+		ref = order.PurchaseOrderNumber
+	}
+	if ref == "" {
+		ref = fmt.Sprintf("orden-%d", order.ID)
+	}
+	// Los patrones de numeración pueden incluir barras.
+	ref = strings.NewReplacer("/", "-", "\\", "-").Replace(ref)
+	return fmt.Sprintf("%s-%s.pdf", kind, ref)
+}
+
+// document envuelve fpdf con los estilos comunes de los tres documentos.
+type document struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string // Traduce UTF-8 a cp1252 para las fuentes estándar
+}
+
+const (
+	pageMargin = 20.0
+	lineHeight = 6.0
+)
+
+func newDocument() *document {
+	pdf := fpdf.New("P", "mm", "Letter", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	pdf.AliasNbPages("")
+	d := &document{pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 10, d.tr(fmt.Sprintf("Página %d de {nb}", pdf.PageNo())), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+	return d
+}
+
+func (d *document) contentWidth() float64 {
+	width, _ := d.pdf.GetPageSize()
+	return width - 2*pageMargin
+}
+
+func (d *document) title(text string) {
+	d.pdf.SetFont("Helvetica", "B", 14)
+	d.pdf.CellFormat(0, 10, d.tr(text), "", 1, "C", false, 0, "")
+	d.pdf.Ln(4)
+}
+
+// field escribe una fila "Etiqueta: valor" y ajusta el valor en varias líneas si es largo.
+func (d *document) field(label, value string) {
+	const labelWidth = 50.0
+	d.pdf.SetFont("Helvetica", "B", 10)
+	d.pdf.CellFormat(labelWidth, lineHeight, d.tr(label+":"), "", 0, "L", false, 0, "")
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.MultiCell(d.contentWidth()-labelWidth, lineHeight, d.tr(value), "", "L", false)
+}
+
+func (d *document) paragraph(text string) {
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.MultiCell(0, lineHeight, d.tr(text), "", "J", false)
+	d.pdf.Ln(2)
+}
+
+func (d *document) section(text string) {
+	d.pdf.Ln(3)
+	d.pdf.SetFont("Helvetica", "B", 11)
+	d.pdf.CellFormat(0, lineHeight+1, d.tr(text), "B", 1, "L", false, 0, "")
+	d.pdf.Ln(2)
+}
+
+// signature dibuja el bloque de firma con el nombre y el cargo del funcionario.
+func (d *document) signature(name, position string) {
+	d.pdf.Ln(20)
+	width := 80.0
+	x := pageMargin + (d.contentWidth()-width)/2
+	y := d.pdf.GetY()
+	d.pdf.Line(x, y, x+width, y)
+	d.pdf.Ln(2)
+	d.pdf.SetFont("Helvetica", "B", 10)
+	d.pdf.CellFormat(0, lineHeight, d.tr(name), "", 1, "C", false, 0, "")
+	d.pdf.SetFont("Helvetica", "", 10)
+	d.pdf.CellFormat(0, lineHeight, d.tr(position), "", 1, "C", false, 0, "")
+}
+
+// itemsTable dibuja la tabla de ítems con los totales de la orden.
+func (d *document) itemsTable(order *models.Order) {
+	widths := []float64{12, 80, 18, 20, 22, 24}
+	headers := []string{"N°", "Descripción", "Cant.", "Unidad", "P. Unit.", "Total"}
+
+	d.pdf.SetFont("Helvetica", "B", 9)
+	d.pdf.SetFillColor(230, 230, 230)
+	for i, h := range headers {
+		d.pdf.CellFormat(widths[i], 7, d.tr(h), "1", 0, "C", true, 0, "")
+	}
+	d.pdf.Ln(-1)
+
+	d.pdf.SetFont("Helvetica", "", 9)
+	for i, item := range order.Items {
+		d.pdf.CellFormat(widths[0], 6, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		d.pdf.CellFormat(widths[1], 6, d.tr(truncate(item.Description, 48)), "1", 0, "L", false, 0, "")
+		d.pdf.CellFormat(widths[2], 6, formatQuantity(item.Quantity), "1", 0, "R", false, 0, "")
+		d.pdf.CellFormat(widths[3], 6, d.tr(item.UnitOfMeasure), "1", 0, "C", false, 0, "")
+		d.pdf.CellFormat(widths[4], 6, formatAmount(item.UnitPrice), "1", 0, "R", false, 0, "")
+		d.pdf.CellFormat(widths[5], 6, formatAmount(item.LineTotal), "1", 1, "R", false, 0, "")
+	}
+
+	labelWidth := widths[0] + widths[1] + widths[2] + widths[3] + widths[4]
+	totals := []struct {
+		label  string
+		amount money.Money
+	}{
+		{"Base Imponible", order.BaseAmount},
+		{"IVA", order.IvaAmount},
+		{"Total", order.TotalAmount},
+	}
+	for _, t := range totals {
+		d.pdf.SetFont("Helvetica", "B", 9)
+		d.pdf.CellFormat(labelWidth, 6, d.tr(t.label), "1", 0, "R", false, 0, "")
+		d.pdf.CellFormat(widths[5], 6, formatAmount(t.amount), "1", 1, "R", false, 0, "")
+	}
+}
+
+// officialSignature obtiene el nombre y el cargo del funcionario responsable,
+// usando el nombre guardado en la orden si el registro ya no existe.
+func officialSignature(order *models.Order) (string, string) {
+	if order.Official != nil {
+		return order.Official.FullName, order.Official.Position.Name
+	}
+	return order.ResponsibleOfficial, ""
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("02/01/2006")
+}
+
+func formatDatePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatDate(*t)
+}
+
+// formatAmount usa el formato venezolano: punto para miles y coma decimal.
+func formatAmount(m money.Money) string {
+	s := m.String()
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	intPart, decPart, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	result := b.String() + "," + decPart
+	if negative {
+		result = "-" + result
+	}
+	return result
+}
+
+func formatQuantity(q decimal.Decimal) string {
+	return strings.Replace(q.String(), ".", ",", 1)
+}
+
+// formatRate muestra la tasa de cambio con sus seis decimales y coma decimal.
+func formatRate(r decimal.Decimal) string {
+	return strings.Replace(r.StringFixed(6), ".", ",", 1)
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,26 @@
+package documents
+
+import "github.com/toor/backend/internal/models"
+
+// renderMemo genera el memorándum de requisición (paso 1).
+func renderMemo(d *document, order *models.Order) {
+	name, position := officialSignature(order)
+
+	d.title("MEMORÁNDUM")
+	d.field("Número", order.MemoNumber)
+	d.field("Fecha", formatDate(order.MemoDate))
+	d.field("De", name)
+	d.field("Unidad Solicitante", order.RequestingUnit)
+	d.field("Asunto", order.Subject)
+
+	d.section("Requerimiento")
+	d.paragraph("Por medio de la presente se solicita la adquisición de lo que a continuación se describe:")
+	d.paragraph(order.Concept)
+
+	if len(order.Items) > 0 {
+		d.section("Detalle")
+		d.itemsTable(order)
+	}
+
+	d.signature(name, position)
+}
@@ -0,0 +1,37 @@
+package documents
+
+import (
+	"strings"
+
+	"github.com/toor/backend/internal/models"
+)
+
+// renderPurchaseOrder genera la orden de compra o de servicio (paso 4).
+func renderPurchaseOrder(d *document, order *models.Order) {
+	name, position := officialSignature(order)
+
+	title := "ORDEN DE COMPRA"
+	if strings.Contains(strings.ToLower(order.DocumentType), "servicio") {
+		title = "ORDEN DE SERVICIO"
+	}
+	d.title(title)
+	d.field("Número", order.PurchaseOrderNumber)
+	d.field("Memo de Referencia", order.MemoNumber)
+	d.field("Unidad Solicitante", order.RequestingUnit)
+	d.field("Concepto", order.Concept)
+
+	d.section("Proveedor")
+	d.field("Razón Social", order.Provider)
+	if order.ProviderRef != nil {
+		d.field("RIF", order.ProviderRef.RIF)
+		d.field("Dirección", order.ProviderRef.Address)
+	}
+	d.field("Presupuesto N°", order.BudgetNumber)
+	d.field("Fecha del Presupuesto", formatDate(order.BudgetDate))
+	d.field("Tiempo de Entrega", order.DeliveryTime)
+
+	d.section("Detalle")
+	d.itemsTable(order)
+
+	d.signature(name, position)
+}
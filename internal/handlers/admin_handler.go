@@ -2,9 +2,10 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/middleware"
 	"github.com/toor/backend/internal/service"
 )
 
@@ -16,21 +17,146 @@ func NewAdminHandler(s service.CounterService) *AdminHandler {
 	return &AdminHandler{counterService: s}
 }
 
-type ResetRequest struct {
-	Year int `json:"year" binding:"required,gte=2024"`
-}
+// ErrResetCountersRemoved responde a la ruta que reiniciaba los contadores,
+// reemplazada por el cierre explícito del ejercicio.
+var ErrResetCountersRemoved = apperror.Gone("ENDPOINT_REMOVED",
+	"este endpoint se retiró; use POST /api/admin/fiscal-years/{year}/close para cerrar el ejercicio")
 
+// ResetCountersHandler responde 410 para que los clientes antiguos sepan que
+// deben cerrar el ejercicio con CloseFiscalYear.
 func (h *AdminHandler) ResetCountersHandler(c *gin.Context) {
-	var req ResetRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+	c.Error(ErrResetCountersRemoved)
+}
+
+func (h *AdminHandler) GetFiscalYears(c *gin.Context) {
+	years, err := h.counterService.GetFiscalYears(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, years)
+}
+
+// CloseFiscalYear cierra el ejercicio de la URL, abre el siguiente y responde
+// con la secuencia final de cada tipo de documento.
+func (h *AdminHandler) CloseFiscalYear(c *gin.Context) {
+	year, ok := pathID(c, "year")
+	if !ok {
+		return
+	}
+
+	closing, err := h.counterService.CloseFiscalYear(c.Request.Context(), middleware.CurrentActor(c), int(year))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, closing)
+}
+
+func (h *AdminHandler) GetCounters(c *gin.Context) {
+	counters, err := h.counterService.ListCounters(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, counters)
+}
+
+// CounterQuery indica la unidad en los tipos que se numeran por unidad.
+type CounterQuery struct {
+	UnitID *uint `form:"unitId"`
+}
+
+// PreviewCounter muestra el próximo número del tipo de la URL sin consumirlo.
+func (h *AdminHandler) PreviewCounter(c *gin.Context) {
+	var query CounterQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	preview, err := h.counterService.PreviewNumber(c.Request.Context(), c.Param("type"), query.UnitID)
+	if err != nil {
+		c.Error(err)
 		return
 	}
+	c.JSON(http.StatusOK, preview)
+}
+
+type AdjustCounterRequest struct {
+	UnitID       *uint  `json:"unitId"`
+	NextSequence uint   `json:"nextSequence" binding:"required"`
+	Reason       string `json:"reason" binding:"required"`
+}
+
+// AdjustCounter hace que la serie del tipo de la URL continúe en el número indicado.
+func (h *AdminHandler) AdjustCounter(c *gin.Context) {
+	var req AdjustCounterRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	preview, err := h.counterService.AdjustSequence(c.Request.Context(), middleware.CurrentActor(c), service.SequenceAdjustment{
+		DocumentType: c.Param("type"),
+		UnitID:       req.UnitID,
+		NextSequence: req.NextSequence,
+		Reason:       req.Reason,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// VoidNumberRequest identifica el número a anular. Year es 0 en las series
+// que no se reinician y UnitID es 0 en las series globales.
+type VoidNumberRequest struct {
+	DocumentType string `json:"documentType" binding:"required"`
+	Year         int    `json:"year"`
+	UnitID       uint   `json:"unitId"`
+	Sequence     uint   `json:"sequence" binding:"required"`
+	Reason       string `json:"reason" binding:"required"`
+}
 
-	if err := h.counterService.PerformAnnualReset(req.Year); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset counters: " + err.Error()})
+// VoidNumber anula un correlativo con su justificación.
+func (h *AdminHandler) VoidNumber(c *gin.Context) {
+	var req VoidNumberRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Annual closing process for year " + strconv.Itoa(req.Year) + " acknowledged. New counters will be generated on demand."})
+	entry, err := h.counterService.VoidNumber(c.Request.Context(), middleware.CurrentActor(c), service.VoidNumberRequest{
+		DocumentType: req.DocumentType,
+		Year:         req.Year,
+		UnitID:       req.UnitID,
+		Sequence:     req.Sequence,
+		Reason:       req.Reason,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// NumberGapsQuery filtra el informe de huecos; sin filtros incluye todas las series.
+type NumberGapsQuery struct {
+	DocumentType string `form:"documentType"`
+	Year         *int   `form:"year"`
+}
+
+// GetNumberGaps informa los números de cada serie que no están asignados a
+// un documento ni anulados.
+func (h *AdminHandler) GetNumberGaps(c *gin.Context) {
+	var query NumberGapsQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+
+	reports, err := h.counterService.GetNumberGaps(c.Request.Context(), query.DocumentType, query.Year)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, reports)
 }
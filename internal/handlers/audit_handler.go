@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/repository"
+	"github.com/toor/backend/internal/service"
+)
+
+type AuditHandler struct {
+	service service.AuditService
+}
+
+func NewAuditHandler(s service.AuditService) *AuditHandler {
+	return &AuditHandler{service: s}
+}
+
+// AuditListQuery son los parámetros de consulta de GET /api/audit.
+// Las fechas van en formato YYYY-MM-DD.
+type AuditListQuery struct {
+	Entity   string `form:"entity"` // Ej: "Provider", "Order"
+	EntityID *uint  `form:"entityId"`
+	Action   string `form:"action"`
+	Actor    string `form:"actor"` // Nombre de usuario
+	UserID   *uint  `form:"userId"`
+	From     string `form:"from"`
+	To       string `form:"to"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (q *AuditListQuery) toFilter() (repository.AuditFilter, error) {
+	filter := repository.AuditFilter{
+		EntityType: q.Entity,
+		EntityID:   q.EntityID,
+		Action:     q.Action,
+		Username:   q.Actor,
+		UserID:     q.UserID,
+		Page:       q.Page,
+		Limit:      q.Limit,
+	}
+	switch q.Action {
+	case "", models.AuditCreate, models.AuditUpdate, models.AuditDelete:
+	default:
+		return filter, ErrInvalidInput.WithMessage(fmt.Sprintf("la acción %q no es válida", q.Action))
+	}
+
+	dates := []struct {
+		param  string
+		value  string
+		target **time.Time
+	}{
+		{"from", q.From, &filter.From},
+		{"to", q.To, &filter.To},
+	}
+	for _, d := range dates {
+		if d.value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", d.value)
+		if err != nil {
+			return filter, ErrInvalidInput.WithMessage(fmt.Sprintf("%s debe tener el formato AAAA-MM-DD", d.param))
+		}
+		*d.target = &t
+	}
+	return filter, nil
+}
+
+func (h *AuditHandler) GetAuditEntries(c *gin.Context) {
+	var query AuditListQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	filter, err := query.toFilter()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	filter.Normalize()
+
+	entries, total, err := h.service.GetEntries(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	totalPages := (total + int64(filter.Limit) - 1) / int64(filter.Limit)
+	c.JSON(http.StatusOK, gin.H{
+		"data":       entries,
+		"total":      total,
+		"page":       filter.Page,
+		"limit":      filter.Limit,
+		"totalPages": totalPages,
+	})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/middleware"
+	"github.com/toor/backend/internal/service"
+)
+
+type AuthHandler struct {
+	service service.AuthService
+}
+
+func NewAuthHandler(s service.AuthService) *AuthHandler {
+	return &AuthHandler{service: s}
+}
+
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken"` // Opcional: también se revoca si se envía
+}
+
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	tokens, err := h.service.Login(c.Request.Context(), req.Username, req.Password)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	tokens, err := h.service.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	actor := middleware.CurrentActor(c)
+	if actor == nil {
+		c.Error(middleware.ErrAuthRequired)
+		return
+	}
+
+	var req LogoutRequest
+	// El cuerpo es opcional.
+	if c.Request.ContentLength > 0 {
+		if !bindJSON(c, &req) {
+			return
+		}
+	}
+
+	if err := h.service.Logout(c.Request.Context(), actor, req.RefreshToken); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Me devuelve el usuario autenticado con su funcionario asociado.
+func (h *AuthHandler) Me(c *gin.Context) {
+	actor := middleware.CurrentActor(c)
+	if actor == nil {
+		c.Error(middleware.ErrAuthRequired)
+		return
+	}
+
+	user, err := h.service.GetUser(c.Request.Context(), actor.UserID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/documents"
+	"github.com/toor/backend/internal/service"
+)
+
+type DocumentHandler struct {
+	service service.DocumentService
+}
+
+func NewDocumentHandler(s service.DocumentService) *DocumentHandler {
+	return &DocumentHandler{service: s}
+}
+
+// GetOrderDocument responde GET /api/orders/:id/documents/{memo|account-point|order}.pdf
+func (h *DocumentHandler) GetOrderDocument(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	kind, err := documents.ParseKind(c.Param("document"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pdf, fileName, err := h.service.RenderOrderDocument(c.Request.Context(), id, kind)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", fileName))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/middleware"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/service"
+)
+
+type DocumentTypeHandler struct {
+	service service.DocumentTypeService
+}
+
+func NewDocumentTypeHandler(s service.DocumentTypeService) *DocumentTypeHandler {
+	return &DocumentTypeHandler{service: s}
+}
+
+// DocumentTypeRequest configura la numeración de un tipo de documento. En la
+// actualización el código es opcional y no puede cambiar.
+type DocumentTypeRequest struct {
+	Code        string `json:"code"`
+	Name        string `json:"name" binding:"required"`
+	Prefix      string `json:"prefix"`
+	Pattern     string `json:"pattern"` // Por defecto {PREFIX}-{YYYY}-{SEQ:5}
+	Scope       string `json:"scope" binding:"omitempty,oneof=GLOBAL UNIT"`
+	ResetPolicy string `json:"resetPolicy" binding:"omitempty,oneof=YEARLY NEVER"`
+}
+
+func (r *DocumentTypeRequest) toModel() *models.DocumentType {
+	return &models.DocumentType{
+		Code:        r.Code,
+		Name:        r.Name,
+		Prefix:      r.Prefix,
+		Pattern:     r.Pattern,
+		Scope:       r.Scope,
+		ResetPolicy: r.ResetPolicy,
+	}
+}
+
+func (h *DocumentTypeHandler) GetDocumentTypes(c *gin.Context) {
+	docTypes, err := h.service.GetAllDocumentTypes(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, docTypes)
+}
+
+func (h *DocumentTypeHandler) CreateDocumentType(c *gin.Context) {
+	var req DocumentTypeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	docType, err := h.service.CreateDocumentType(c.Request.Context(), middleware.CurrentActor(c), req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, docType)
+}
+
+func (h *DocumentTypeHandler) UpdateDocumentType(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req DocumentTypeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	docType, err := h.service.UpdateDocumentType(c.Request.Context(), middleware.CurrentActor(c), id, req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, docType)
+}
+
+func (h *DocumentTypeHandler) DeleteDocumentType(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteDocumentType(c.Request.Context(), middleware.CurrentActor(c), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
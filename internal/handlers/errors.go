@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/toor/backend/internal/apperror"
+)
+
+// Los handlers no escriben las respuestas de error: registran el error con
+// c.Error y middleware.Errors lo traduce a {code, message, details, requestId}.
+
+var (
+	// ErrInvalidInput indica que el cuerpo o los parámetros de consulta no son válidos.
+	ErrInvalidInput = apperror.Validation("INVALID_INPUT", "los datos enviados no son válidos")
+	// ErrInvalidID indica que un identificador de la URL no es un número válido.
+	ErrInvalidID = apperror.Validation("INVALID_ID", "el identificador de la URL no es válido")
+	// ErrRouteNotFound indica que la ruta no existe.
+	ErrRouteNotFound = apperror.NotFound("ROUTE_NOT_FOUND", "la ruta no existe")
+)
+
+// FieldError describe un campo inválido en los detalles de ErrInvalidInput.
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+func init() {
+	// Los errores de validación usan el nombre del campo en el JSON (o en la
+	// consulta) en lugar del nombre del campo en Go.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form"} {
+				name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+				if name != "" && name != "-" {
+					return name
+				}
+			}
+			return field.Name
+		})
+	}
+}
+
+// NotFound responde las rutas inexistentes con el formato de error de la API.
+func NotFound(c *gin.Context) {
+	c.Error(ErrRouteNotFound)
+}
+
+// bindJSON decodifica el cuerpo en obj. Si no es válido registra el error y
+// devuelve false; el handler solo debe retornar.
+func bindJSON(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		c.Error(invalidInput(err))
+		return false
+	}
+	return true
+}
+
+// bindQuery es bindJSON para los parámetros de consulta.
+func bindQuery(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindQuery(obj); err != nil {
+		c.Error(invalidInput(err))
+		return false
+	}
+	return true
+}
+
+// pathID lee el parámetro numérico name de la URL. Si no es válido registra
+// el error y devuelve false.
+func pathID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		c.Error(ErrInvalidID.WithDetails(FieldError{Field: name, Reason: "debe ser un número entero positivo"}))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// invalidInput convierte un error de decodificación o de validación en
+// ErrInvalidInput, con la lista de campos inválidos cuando se conocen.
+func invalidInput(err error) error {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var numErr *strconv.NumError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrs):
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, FieldError{Field: fe.Field(), Reason: validationReason(fe)})
+		}
+		return ErrInvalidInput.WithDetails(fields).Wrap(err)
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = "body"
+		}
+		return ErrInvalidInput.WithDetails([]FieldError{{Field: field, Reason: "tipo de dato inválido"}}).Wrap(err)
+	case errors.As(err, &numErr):
+		return ErrInvalidInput.WithMessage(fmt.Sprintf("los datos enviados no son válidos: %q no es un número", numErr.Num)).Wrap(err)
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrInvalidInput.WithMessage("el cuerpo de la petición no es un JSON válido").Wrap(err)
+	case errors.Is(err, io.EOF):
+		return ErrInvalidInput.WithMessage("el cuerpo de la petición está vacío").Wrap(err)
+	default:
+		return ErrInvalidInput.Wrap(err)
+	}
+}
+
+// validationReason describe en español la regla de validación que falló.
+func validationReason(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "es obligatorio"
+	case "min", "gte":
+		return "debe ser mayor o igual a " + fe.Param()
+	case "max", "lte":
+		return "debe ser menor o igual a " + fe.Param()
+	case "gt":
+		return "debe ser mayor que " + fe.Param()
+	case "lt":
+		return "debe ser menor que " + fe.Param()
+	case "len":
+		return "debe tener longitud " + fe.Param()
+	case "oneof":
+		return "debe ser uno de: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "email":
+		return "debe ser un correo electrónico válido"
+	default:
+		return "no cumple la regla " + fe.Tag()
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/middleware"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/service"
+)
+
+type ExchangeRateHandler struct {
+	service service.ExchangeRateService
+}
+
+func NewExchangeRateHandler(s service.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{service: s}
+}
+
+type ExchangeRateRequest struct {
+	Date     time.Time       `json:"date" binding:"required"`
+	Currency string          `json:"currency" binding:"required"`
+	Rate     decimal.Decimal `json:"rate"` // Se valida en el servicio (mayor que cero)
+	Source   string          `json:"source"`
+}
+
+func (r *ExchangeRateRequest) toModel() *models.ExchangeRate {
+	return &models.ExchangeRate{
+		Date:     r.Date,
+		Currency: r.Currency,
+		Rate:     r.Rate,
+		Source:   r.Source,
+	}
+}
+
+// GetExchangeRates lista las tasas cargadas; admite ?currency=USD.
+func (h *ExchangeRateHandler) GetExchangeRates(c *gin.Context) {
+	rates, err := h.service.GetAllExchangeRates(c.Request.Context(), c.Query("currency"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+func (h *ExchangeRateHandler) CreateExchangeRate(c *gin.Context) {
+	var req ExchangeRateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rate, err := h.service.CreateExchangeRate(c.Request.Context(), middleware.CurrentActor(c), req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, rate)
+}
+
+func (h *ExchangeRateHandler) UpdateExchangeRate(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req ExchangeRateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rate, err := h.service.UpdateExchangeRate(c.Request.Context(), middleware.CurrentActor(c), id, req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
+
+func (h *ExchangeRateHandler) DeleteExchangeRate(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteExchangeRate(c.Request.Context(), middleware.CurrentActor(c), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// UploadExchangeRates carga tasas desde un CSV enviado en el campo "file"
+// (multipart/form-data) con encabezado date,currency,rate[,source].
+func (h *ExchangeRateHandler) UploadExchangeRates(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	rates, err := h.service.ImportCSV(c.Request.Context(), middleware.CurrentActor(c), file)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"imported": len(rates), "data": rates})
+}
@@ -2,10 +2,9 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/middleware"
 	"github.com/toor/backend/internal/models"
 	"github.com/toor/backend/internal/service"
 )
@@ -21,50 +20,48 @@ func NewMasterDataHandler(s service.MasterDataService) *MasterDataHandler {
 // --- Units ---
 func (h *MasterDataHandler) CreateUnit(c *gin.Context) {
 	var unit models.Unit
-	if err := c.ShouldBindJSON(&unit); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &unit) {
 		return
 	}
-	created, err := h.service.CreateUnit(&unit)
+	created, err := h.service.CreateUnit(c.Request.Context(), middleware.CurrentActor(c), &unit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusCreated, created)
 }
 func (h *MasterDataHandler) GetUnits(c *gin.Context) {
-	units, err := h.service.GetAllUnits()
+	units, err := h.service.GetAllUnits(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, units)
 }
 func (h *MasterDataHandler) UpdateUnit(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
 	var unit models.Unit
-	if err := c.ShouldBindJSON(&unit); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &unit) {
 		return
 	}
-	updated, err := h.service.UpdateUnit(uint(id), &unit)
+	updated, err := h.service.UpdateUnit(c.Request.Context(), middleware.CurrentActor(c), id, &unit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, updated)
 }
 func (h *MasterDataHandler) DeleteUnit(c *gin.Context) {
-	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	err := h.service.DeleteUnit(uint(id))
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	err := h.service.DeleteUnit(c.Request.Context(), middleware.CurrentActor(c), id)
 	if err != nil {
-		// CAMBIO CLAVE: Si es nuestro error de negocio, devolvemos 409 Conflict
-		if strings.Contains(err.Error(), "asignada a uno o más funcionarios") {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-			return
-		}
-		// Para cualquier otro error, devolvemos 500 Internal Server Error
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete unit"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusNoContent, nil)
@@ -73,49 +70,49 @@ func (h *MasterDataHandler) DeleteUnit(c *gin.Context) {
 // --- Positions ---
 func (h *MasterDataHandler) CreatePosition(c *gin.Context) {
 	var pos models.Position
-	if err := c.ShouldBindJSON(&pos); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &pos) {
 		return
 	}
-	created, err := h.service.CreatePosition(&pos)
+	created, err := h.service.CreatePosition(c.Request.Context(), middleware.CurrentActor(c), &pos)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusCreated, created)
 }
 func (h *MasterDataHandler) GetPositions(c *gin.Context) {
-	positions, err := h.service.GetAllPositions()
+	positions, err := h.service.GetAllPositions(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, positions)
 }
 func (h *MasterDataHandler) UpdatePosition(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
 	var pos models.Position
-	if err := c.ShouldBindJSON(&pos); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &pos) {
 		return
 	}
-	updated, err := h.service.UpdatePosition(uint(id), &pos)
+	updated, err := h.service.UpdatePosition(c.Request.Context(), middleware.CurrentActor(c), id, &pos)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, updated)
 }
 
 func (h *MasterDataHandler) DeletePosition(c *gin.Context) {
-	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	err := h.service.DeletePosition(uint(id))
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	err := h.service.DeletePosition(c.Request.Context(), middleware.CurrentActor(c), id)
 	if err != nil {
-		if strings.Contains(err.Error(), "asignado a uno o más funcionarios") {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete position"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusNoContent, nil)
@@ -124,43 +121,47 @@ func (h *MasterDataHandler) DeletePosition(c *gin.Context) {
 // --- Officials ---
 func (h *MasterDataHandler) CreateOfficial(c *gin.Context) {
 	var off models.Official
-	if err := c.ShouldBindJSON(&off); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &off) {
 		return
 	}
-	created, err := h.service.CreateOfficial(&off)
+	created, err := h.service.CreateOfficial(c.Request.Context(), middleware.CurrentActor(c), &off)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusCreated, created)
 }
 func (h *MasterDataHandler) GetOfficials(c *gin.Context) {
-	officials, err := h.service.GetAllOfficials()
+	officials, err := h.service.GetAllOfficials(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, officials)
 }
 func (h *MasterDataHandler) UpdateOfficial(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
 	var off models.Official
-	if err := c.ShouldBindJSON(&off); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &off) {
 		return
 	}
-	updated, err := h.service.UpdateOfficial(uint(id), &off)
+	updated, err := h.service.UpdateOfficial(c.Request.Context(), middleware.CurrentActor(c), id, &off)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, updated)
 }
 func (h *MasterDataHandler) DeleteOfficial(c *gin.Context) {
-	id, _ := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err := h.service.DeleteOfficial(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete official"})
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.service.DeleteOfficial(c.Request.Context(), middleware.CurrentActor(c), id); err != nil {
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusNoContent, nil)
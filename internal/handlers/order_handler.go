@@ -1,15 +1,17 @@
 package handlers
 
 import (
-	"errors"
-	"log"
+	"fmt"
 	"net/http"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/middleware"
 	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/money"
+	"github.com/toor/backend/internal/repository"
 	"github.com/toor/backend/internal/service"
-	"gorm.io/gorm" 
 )
 
 type OrderHandler struct {
@@ -22,62 +24,214 @@ func NewOrderHandler(s service.OrderService) *OrderHandler {
 
 func (h *OrderHandler) CreateOrderHandler(c *gin.Context) {
 	var order models.Order
-	if err := c.ShouldBindJSON(&order); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+	if !bindJSON(c, &order) {
 		return
 	}
 
-	newOrder, err := h.service.CreateOrder(&order)
+	newOrder, err := h.service.CreateOrder(c.Request.Context(), middleware.CurrentActor(c), &order)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create order: " + err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, newOrder)
 }
 
+// OrderListQuery son los parámetros de consulta de GET /api/orders.
+// Las fechas van en formato YYYY-MM-DD y status admite varios valores separados por comas.
+type OrderListQuery struct {
+	Status         string `form:"status"`
+	MemoDateFrom   string `form:"memoDateFrom"`
+	MemoDateTo     string `form:"memoDateTo"`
+	BudgetDateFrom string `form:"budgetDateFrom"`
+	BudgetDateTo   string `form:"budgetDateTo"`
+	ProviderID     *uint  `form:"providerId"`
+	Provider       string `form:"provider"`
+	UnitID         *uint  `form:"unitId"`
+	RequestingUnit string `form:"requestingUnit"`
+	MinAmount      string `form:"minAmount"` // Monto con punto decimal (ej. 1234.56)
+	MaxAmount      string `form:"maxAmount"`
+	Search         string `form:"q"`
+	Sort           string `form:"sort"`
+	Page           int    `form:"page" binding:"omitempty,min=1"`
+	Limit          int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (q *OrderListQuery) toFilter() (repository.OrderFilter, error) {
+	filter := repository.OrderFilter{
+		ProviderID:     q.ProviderID,
+		Provider:       q.Provider,
+		UnitID:         q.UnitID,
+		RequestingUnit: q.RequestingUnit,
+		Search:         q.Search,
+		Sort:           q.Sort,
+		Page:           q.Page,
+		Limit:          q.Limit,
+	}
+	if q.Status != "" {
+		for _, status := range strings.Split(q.Status, ",") {
+			filter.Statuses = append(filter.Statuses, strings.TrimSpace(status))
+		}
+	}
+	if !repository.IsValidOrderSort(q.Sort) {
+		return filter, ErrInvalidInput.WithMessage(fmt.Sprintf("el orden %q no es válido", q.Sort))
+	}
+
+	dates := []struct {
+		param  string
+		value  string
+		target **time.Time
+	}{
+		{"memoDateFrom", q.MemoDateFrom, &filter.MemoDateFrom},
+		{"memoDateTo", q.MemoDateTo, &filter.MemoDateTo},
+		{"budgetDateFrom", q.BudgetDateFrom, &filter.BudgetDateFrom},
+		{"budgetDateTo", q.BudgetDateTo, &filter.BudgetDateTo},
+	}
+	for _, d := range dates {
+		if d.value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", d.value)
+		if err != nil {
+			return filter, ErrInvalidInput.WithMessage(fmt.Sprintf("%s debe tener el formato AAAA-MM-DD", d.param))
+		}
+		*d.target = &t
+	}
+
+	amounts := []struct {
+		param  string
+		value  string
+		target **money.Money
+	}{
+		{"minAmount", q.MinAmount, &filter.MinAmount},
+		{"maxAmount", q.MaxAmount, &filter.MaxAmount},
+	}
+	for _, a := range amounts {
+		if a.value == "" {
+			continue
+		}
+		m, err := money.Parse(a.value)
+		if err != nil {
+			return filter, ErrInvalidInput.WithMessage(fmt.Sprintf("%s debe ser un monto con punto decimal", a.param))
+		}
+		*a.target = &m
+	}
+	return filter, nil
+}
+
 func (h *OrderHandler) GetOrdersHandler(c *gin.Context) {
-	orders, err := h.service.GetAllOrders()
+	var query OrderListQuery
+	if !bindQuery(c, &query) {
+		return
+	}
+	filter, err := query.toFilter()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve orders"})
+		c.Error(err)
 		return
 	}
+	filter.Normalize()
 
-	c.JSON(http.StatusOK, orders)
+	orders, total, err := h.service.GetAllOrders(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	totalPages := (total + int64(filter.Limit) - 1) / int64(filter.Limit)
+	c.JSON(http.StatusOK, gin.H{
+		"data":       orders,
+		"total":      total,
+		"page":       filter.Page,
+		"limit":      filter.Limit,
+		"totalPages": totalPages,
+	})
 }
 
 func (h *OrderHandler) GetOrderByIdHandler(c *gin.Context) {
-	// 1. Obtener el ID de la URL
-	idStr := c.Param("id")
-	
-	// --- LÍNEA DE DEPURACIÓN 1 ---
-	log.Printf("Received request for order ID: '%s'\n", idStr)
-	// ------------------------------
-
-	id, err := strconv.ParseUint(idStr, 10, 32)
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	order, err := h.service.GetOrderById(c.Request.Context(), id)
 	if err != nil {
-		log.Printf("Error parsing ID '%s': %v\n", idStr, err) // Log del error de parseo
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		c.Error(err)
 		return
 	}
 
-	// 2. Llamar al servicio
-	order, err := h.service.GetOrderById(uint(id))
+	c.JSON(http.StatusOK, order)
+}
+
+// UpdateOrderHandler reemplaza los datos editables de la orden (PUT).
+func (h *OrderHandler) UpdateOrderHandler(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var input models.Order
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	order, err := h.service.UpdateOrder(c.Request.Context(), middleware.CurrentActor(c), id, &input)
 	if err != nil {
-		// --- LÍNEA DE DEPURACIÓN 2 ---
-		log.Printf("Error from service for ID %d: %v\n", id, err)
-		// ------------------------------
+		c.Error(err)
+		return
+	}
 
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
-			return
-		}
-		
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve order"})
+	c.JSON(http.StatusOK, order)
+}
+
+// PatchOrderHandler actualiza solo los campos presentes en el cuerpo (PATCH),
+// lo que permite guardar la orden paso a paso.
+func (h *OrderHandler) PatchOrderHandler(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	current, err := h.service.GetOrderById(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Al decodificar sobre la orden actual, los campos ausentes conservan su valor.
+	if !bindJSON(c, current) {
+		return
+	}
+
+	order, err := h.service.UpdateOrder(c.Request.Context(), middleware.CurrentActor(c), id, current)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	// 4. Devolver la orden
-	log.Printf("Successfully found and returned order with ID %d\n", id) // Log de éxito
 	c.JSON(http.StatusOK, order)
-}
\ No newline at end of file
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// CancelOrderHandler anula la orden sin eliminarla.
+func (h *OrderHandler) CancelOrderHandler(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req CancelOrderRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	order, err := h.service.CancelOrder(c.Request.Context(), middleware.CurrentActor(c), id, req.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
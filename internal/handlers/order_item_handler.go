@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/middleware"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/money"
+)
+
+type OrderItemRequest struct {
+	Description   string          `json:"description" binding:"required"`
+	Quantity      decimal.Decimal `json:"quantity"` // Se valida en el servicio (> 0)
+	UnitOfMeasure string          `json:"unitOfMeasure"`
+	UnitPrice     money.Money     `json:"unitPrice"` // Se valida en el servicio (>= 0)
+	TaxCode       string          `json:"taxCode" binding:"omitempty,oneof=GENERAL REDUCIDA"`
+	TaxExempt     bool            `json:"taxExempt"`
+}
+
+func (r *OrderItemRequest) toModel() *models.OrderItem {
+	return &models.OrderItem{
+		Description:   r.Description,
+		Quantity:      r.Quantity,
+		UnitOfMeasure: r.UnitOfMeasure,
+		UnitPrice:     r.UnitPrice,
+		TaxCode:       r.TaxCode,
+		TaxExempt:     r.TaxExempt,
+	}
+}
+
+func (h *OrderHandler) GetOrderItemsHandler(c *gin.Context) {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	items, err := h.service.GetOrderItems(c.Request.Context(), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *OrderHandler) CreateOrderItemHandler(c *gin.Context) {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req OrderItemRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	item, err := h.service.AddOrderItem(c.Request.Context(), middleware.CurrentActor(c), orderID, req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func (h *OrderHandler) UpdateOrderItemHandler(c *gin.Context) {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(c, "itemId")
+	if !ok {
+		return
+	}
+
+	var req OrderItemRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	item, err := h.service.UpdateOrderItem(c.Request.Context(), middleware.CurrentActor(c), orderID, itemID, req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *OrderHandler) DeleteOrderItemHandler(c *gin.Context) {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	itemID, ok := pathID(c, "itemId")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteOrderItem(c.Request.Context(), middleware.CurrentActor(c), orderID, itemID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/middleware"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/money"
+)
+
+type QuotationRequest struct {
+	ProviderID   uint        `json:"providerId" binding:"required"`
+	DocumentType string      `json:"documentType"`
+	BudgetNumber string      `json:"budgetNumber" binding:"required"`
+	BudgetDate   time.Time   `json:"budgetDate"`
+	Currency     string      `json:"currency" binding:"omitempty,oneof=VES USD EUR"`
+	Amount       money.Money `json:"amount"` // Se valida en el servicio (> 0)
+	DeliveryTime string      `json:"deliveryTime"`
+	DeliveryDays int         `json:"deliveryDays" binding:"min=0"`
+	OfferQuality string      `json:"offerQuality"`
+	QualityScore int         `json:"qualityScore" binding:"min=0,max=5"`
+	Notes        string      `json:"notes"`
+}
+
+func (r *QuotationRequest) toModel() *models.Quotation {
+	return &models.Quotation{
+		ProviderID:   r.ProviderID,
+		DocumentType: r.DocumentType,
+		BudgetNumber: r.BudgetNumber,
+		BudgetDate:   r.BudgetDate,
+		Currency:     r.Currency,
+		Amount:       r.Amount,
+		DeliveryTime: r.DeliveryTime,
+		DeliveryDays: r.DeliveryDays,
+		OfferQuality: r.OfferQuality,
+		QualityScore: r.QualityScore,
+		Notes:        r.Notes,
+	}
+}
+
+// QuotationScoreRequest es la evaluación del analista para el cuadro comparativo.
+type QuotationScoreRequest struct {
+	DeliveryDays int `json:"deliveryDays" binding:"min=0"`
+	QualityScore int `json:"qualityScore" binding:"min=0,max=5"`
+}
+
+func (h *OrderHandler) GetOrderQuotationsHandler(c *gin.Context) {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	quotations, err := h.service.GetOrderQuotations(c.Request.Context(), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quotations)
+}
+
+func (h *OrderHandler) CreateOrderQuotationHandler(c *gin.Context) {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req QuotationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	quotation, err := h.service.AddOrderQuotation(c.Request.Context(), middleware.CurrentActor(c), orderID, req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, quotation)
+}
+
+func (h *OrderHandler) UpdateOrderQuotationHandler(c *gin.Context) {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	quotationID, ok := pathID(c, "quotationId")
+	if !ok {
+		return
+	}
+
+	var req QuotationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	quotation, err := h.service.UpdateOrderQuotation(c.Request.Context(), middleware.CurrentActor(c), orderID, quotationID, req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quotation)
+}
+
+func (h *OrderHandler) ScoreOrderQuotationHandler(c *gin.Context) {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	quotationID, ok := pathID(c, "quotationId")
+	if !ok {
+		return
+	}
+
+	var req QuotationScoreRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	quotation, err := h.service.ScoreOrderQuotation(c.Request.Context(), middleware.CurrentActor(c), orderID, quotationID, req.DeliveryDays, req.QualityScore)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quotation)
+}
+
+func (h *OrderHandler) DeleteOrderQuotationHandler(c *gin.Context) {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	quotationID, ok := pathID(c, "quotationId")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteOrderQuotation(c.Request.Context(), middleware.CurrentActor(c), orderID, quotationID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// GetQuotationComparisonHandler devuelve el cuadro comparativo de la orden.
+func (h *OrderHandler) GetQuotationComparisonHandler(c *gin.Context) {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	comparison, err := h.service.CompareOrderQuotations(c.Request.Context(), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// SelectOrderQuotationHandler marca la cotización ganadora y devuelve la orden actualizada.
+func (h *OrderHandler) SelectOrderQuotationHandler(c *gin.Context) {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+	quotationID, ok := pathID(c, "quotationId")
+	if !ok {
+		return
+	}
+
+	order, err := h.service.SelectOrderQuotation(c.Request.Context(), middleware.CurrentActor(c), orderID, quotationID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/middleware"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/money"
+	"github.com/toor/backend/internal/service"
+)
+
+type ProcurementHandler struct {
+	service service.ProcurementService
+}
+
+func NewProcurementHandler(s service.ProcurementService) *ProcurementHandler {
+	return &ProcurementHandler{service: s}
+}
+
+type TaxUnitRequest struct {
+	ValidFrom time.Time   `json:"validFrom" binding:"required"`
+	Value     money.Money `json:"value"` // Se valida en el servicio (> 0)
+	Gazette   string      `json:"gazette"`
+}
+
+func (r *TaxUnitRequest) toModel() *models.TaxUnitValue {
+	return &models.TaxUnitValue{ValidFrom: r.ValidFrom, Value: r.Value, Gazette: r.Gazette}
+}
+
+type ThresholdRequest struct {
+	ContractType string           `json:"contractType" binding:"required,oneof=BIENES SERVICIOS OBRAS"`
+	Modality     string           `json:"modality" binding:"required"`
+	MaxUT        *decimal.Decimal `json:"maxUt"` // null = sin límite superior
+}
+
+func (r *ThresholdRequest) toModel() *models.ProcurementThreshold {
+	return &models.ProcurementThreshold{ContractType: r.ContractType, Modality: r.Modality, MaxUT: r.MaxUT}
+}
+
+// --- Unidad tributaria ---
+
+func (h *ProcurementHandler) GetTaxUnits(c *gin.Context) {
+	values, err := h.service.GetAllTaxUnits(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, values)
+}
+
+func (h *ProcurementHandler) CreateTaxUnit(c *gin.Context) {
+	var req TaxUnitRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	value, err := h.service.CreateTaxUnit(c.Request.Context(), middleware.CurrentActor(c), req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, value)
+}
+
+func (h *ProcurementHandler) UpdateTaxUnit(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req TaxUnitRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	value, err := h.service.UpdateTaxUnit(c.Request.Context(), middleware.CurrentActor(c), id, req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, value)
+}
+
+func (h *ProcurementHandler) DeleteTaxUnit(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTaxUnit(c.Request.Context(), middleware.CurrentActor(c), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// --- Umbrales por modalidad ---
+
+func (h *ProcurementHandler) GetThresholds(c *gin.Context) {
+	thresholds, err := h.service.GetAllThresholds(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, thresholds)
+}
+
+func (h *ProcurementHandler) CreateThreshold(c *gin.Context) {
+	var req ThresholdRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	threshold, err := h.service.CreateThreshold(c.Request.Context(), middleware.CurrentActor(c), req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, threshold)
+}
+
+func (h *ProcurementHandler) UpdateThreshold(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req ThresholdRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	threshold, err := h.service.UpdateThreshold(c.Request.Context(), middleware.CurrentActor(c), id, req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, threshold)
+}
+
+func (h *ProcurementHandler) DeleteThreshold(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteThreshold(c.Request.Context(), middleware.CurrentActor(c), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
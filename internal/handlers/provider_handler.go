@@ -1,14 +1,12 @@
 package handlers
 
 import (
-	"errors"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/middleware"
 	"github.com/toor/backend/internal/models"
 	"github.com/toor/backend/internal/service"
-	"gorm.io/gorm"
 )
 
 type ProviderHandler struct {
@@ -20,27 +18,28 @@ func NewProviderHandler(s service.ProviderService) *ProviderHandler {
 }
 
 type ProviderRequest struct {
-	Name    string `json:"name" binding:"required"`
-	RIF     string `json:"rif"`
-	Address string `json:"address"`
+	Name     string `json:"name" binding:"required"`
+	RIF      string `json:"rif"`
+	Address  string `json:"address"`
+	IsActive *bool  `json:"isActive"` // Si se omite: activo al crear, sin cambios al editar
 }
 
 func (h *ProviderHandler) CreateProvider(c *gin.Context) {
 	var req ProviderRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	provider := &models.Provider{
-		Name:    req.Name,
-		RIF:     req.RIF,
-		Address: req.Address,
+		Name:     req.Name,
+		RIF:      req.RIF,
+		Address:  req.Address,
+		IsActive: req.IsActive == nil || *req.IsActive,
 	}
 
-	newProvider, err := h.service.CreateProvider(provider)
+	newProvider, err := h.service.CreateProvider(c.Request.Context(), middleware.CurrentActor(c), provider)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create provider"})
+		c.Error(err)
 		return
 	}
 
@@ -48,28 +47,23 @@ func (h *ProviderHandler) CreateProvider(c *gin.Context) {
 }
 
 func (h *ProviderHandler) GetProviders(c *gin.Context) {
-	providers, err := h.service.GetAllProviders()
+	providers, err := h.service.GetAllProviders(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve providers"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, providers)
 }
 
 func (h *ProviderHandler) GetProvider(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+	id, ok := pathID(c, "id")
+	if !ok {
 		return
 	}
 
-	provider, err := h.service.GetProviderByID(uint(id))
+	provider, err := h.service.GetProviderByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Provider not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve provider"})
+		c.Error(err)
 		return
 	}
 
@@ -77,31 +71,32 @@ func (h *ProviderHandler) GetProvider(c *gin.Context) {
 }
 
 func (h *ProviderHandler) UpdateProvider(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+	id, ok := pathID(c, "id")
+	if !ok {
 		return
 	}
 
 	var req ProviderRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	providerToUpdate, err := h.service.GetProviderByID(uint(id))
+	providerToUpdate, err := h.service.GetProviderByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Provider not found"})
+		c.Error(err)
 		return
 	}
 
 	providerToUpdate.Name = req.Name
 	providerToUpdate.RIF = req.RIF
 	providerToUpdate.Address = req.Address
+	if req.IsActive != nil {
+		providerToUpdate.IsActive = *req.IsActive
+	}
 
-	updatedProvider, err := h.service.UpdateProvider(providerToUpdate)
+	updatedProvider, err := h.service.UpdateProvider(c.Request.Context(), middleware.CurrentActor(c), providerToUpdate)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update provider"})
+		c.Error(err)
 		return
 	}
 
@@ -109,14 +104,13 @@ func (h *ProviderHandler) UpdateProvider(c *gin.Context) {
 }
 
 func (h *ProviderHandler) DeleteProvider(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider ID"})
+	id, ok := pathID(c, "id")
+	if !ok {
 		return
 	}
 
-	if err := h.service.DeleteProvider(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete provider"})
+	if err := h.service.DeleteProvider(c.Request.Context(), middleware.CurrentActor(c), id); err != nil {
+		c.Error(err)
 		return
 	}
 
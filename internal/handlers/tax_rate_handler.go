@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/middleware"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/service"
+)
+
+type TaxRateHandler struct {
+	service service.TaxRateService
+}
+
+func NewTaxRateHandler(s service.TaxRateService) *TaxRateHandler {
+	return &TaxRateHandler{service: s}
+}
+
+type TaxRateRequest struct {
+	Code      string          `json:"code" binding:"required,oneof=GENERAL REDUCIDA"`
+	Name      string          `json:"name" binding:"required"`
+	Rate      decimal.Decimal `json:"rate"` // Se valida en el servicio (0 a 100)
+	ValidFrom time.Time       `json:"validFrom" binding:"required"`
+	ValidTo   *time.Time      `json:"validTo"`
+}
+
+func (r *TaxRateRequest) toModel() *models.TaxRate {
+	return &models.TaxRate{
+		Code:      r.Code,
+		Name:      r.Name,
+		Rate:      r.Rate,
+		ValidFrom: r.ValidFrom,
+		ValidTo:   r.ValidTo,
+	}
+}
+
+func (h *TaxRateHandler) GetTaxRates(c *gin.Context) {
+	rates, err := h.service.GetAllTaxRates(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+func (h *TaxRateHandler) CreateTaxRate(c *gin.Context) {
+	var req TaxRateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rate, err := h.service.CreateTaxRate(c.Request.Context(), middleware.CurrentActor(c), req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, rate)
+}
+
+func (h *TaxRateHandler) UpdateTaxRate(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req TaxRateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rate, err := h.service.UpdateTaxRate(c.Request.Context(), middleware.CurrentActor(c), id, req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rate)
+}
+
+func (h *TaxRateHandler) DeleteTaxRate(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTaxRate(c.Request.Context(), middleware.CurrentActor(c), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/middleware"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/service"
+)
+
+// UserHandler administra usuarios, roles y la asignación de permisos.
+type UserHandler struct {
+	service service.UserService
+}
+
+func NewUserHandler(s service.UserService) *UserHandler {
+	return &UserHandler{service: s}
+}
+
+type RoleRequest struct {
+	Code        string   `json:"code" binding:"required"`
+	Name        string   `json:"name" binding:"required"`
+	Permissions []string `json:"permissions"` // Códigos de permiso
+}
+
+type CreateUserRequest struct {
+	Username   string `json:"username" binding:"required"`
+	Password   string `json:"password" binding:"required"`
+	OfficialID *uint  `json:"officialId"`
+	RoleIDs    []uint `json:"roleIds"`
+}
+
+type UpdateUserRequest struct {
+	Password   string `json:"password"` // Vacía = no se cambia
+	IsActive   bool   `json:"isActive"`
+	OfficialID *uint  `json:"officialId"`
+	RoleIDs    []uint `json:"roleIds"`
+}
+
+// --- Roles y permisos ---
+
+func (h *UserHandler) GetPermissions(c *gin.Context) {
+	permissions, err := h.service.GetAllPermissions(c.Request.Context(), middleware.CurrentActor(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, permissions)
+}
+
+func (h *UserHandler) GetRoles(c *gin.Context) {
+	roles, err := h.service.GetAllRoles(c.Request.Context(), middleware.CurrentActor(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+func (h *UserHandler) CreateRole(c *gin.Context) {
+	var req RoleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	role, err := h.service.CreateRole(c.Request.Context(), middleware.CurrentActor(c), &models.Role{Code: req.Code, Name: req.Name}, req.Permissions)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, role)
+}
+
+func (h *UserHandler) UpdateRole(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req RoleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	role, err := h.service.UpdateRole(c.Request.Context(), middleware.CurrentActor(c), id, &models.Role{Code: req.Code, Name: req.Name}, req.Permissions)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
+
+func (h *UserHandler) DeleteRole(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteRole(c.Request.Context(), middleware.CurrentActor(c), id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// --- Usuarios ---
+
+func (h *UserHandler) GetUsers(c *gin.Context) {
+	users, err := h.service.GetAllUsers(c.Request.Context(), middleware.CurrentActor(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var req CreateUserRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user := &models.User{Username: req.Username, OfficialID: req.OfficialID, IsActive: true}
+	created, err := h.service.CreateUser(c.Request.Context(), middleware.CurrentActor(c), user, req.Password, req.RoleIDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id, ok := pathID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req UpdateUserRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user := &models.User{OfficialID: req.OfficialID, IsActive: req.IsActive}
+	updated, err := h.service.UpdateUser(c.Request.Context(), middleware.CurrentActor(c), id, user, req.Password, req.RoleIDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
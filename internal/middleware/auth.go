@@ -0,0 +1,81 @@
+// Package middleware reúne los middlewares de gin propios de la API.
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/service"
+)
+
+// actorKey es la clave del contexto de gin donde se guarda el usuario autenticado.
+const actorKey = "auth.actor"
+
+var (
+	// ErrAuthRequired indica que la petición no trae un token de acceso.
+	ErrAuthRequired = apperror.Unauthorized("AUTH_REQUIRED", "debe iniciar sesión")
+	// ErrMissingPermission indica que el usuario no tiene el permiso que exige la ruta.
+	ErrMissingPermission = apperror.Forbidden("MISSING_PERMISSION", "no tiene permiso para realizar esta operación")
+)
+
+// RequireAuth rechaza con 401 las peticiones sin un token de acceso válido
+// en la cabecera "Authorization: Bearer <token>".
+func RequireAuth(authService service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			abortWithError(c, ErrAuthRequired)
+			return
+		}
+
+		actor, err := authService.Authenticate(c.Request.Context(), strings.TrimSpace(token))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		actor.IP = c.ClientIP()
+		c.Set(actorKey, actor)
+		c.Next()
+	}
+}
+
+// RequirePermission rechaza con 403 si el usuario no tiene el permiso. Debe
+// usarse después de RequireAuth.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !CurrentActor(c).Can(permission) {
+			abortWithError(c, ErrMissingPermission.WithDetails(gin.H{"permission": permission}))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAccess exige readPermission para GET y HEAD y writePermission para
+// el resto de los métodos. Se aplica a un grupo de rutas completo.
+func RequireAccess(readPermission, writePermission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permission := writePermission
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			permission = readPermission
+		}
+		if !CurrentActor(c).Can(permission) {
+			abortWithError(c, ErrMissingPermission.WithDetails(gin.H{"permission": permission}))
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentActor devuelve el usuario autenticado por RequireAuth, o nil si no lo hay.
+func CurrentActor(c *gin.Context) *service.Actor {
+	value, ok := c.Get(actorKey)
+	if !ok {
+		return nil
+	}
+	actor, _ := value.(*service.Actor)
+	return actor
+}
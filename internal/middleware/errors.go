@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/toor/backend/internal/apperror"
+)
+
+// RequestIDHeader es la cabecera con el identificador de la petición. Si el
+// cliente (o un proxy) la envía se respeta; si no, se genera una.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey es la clave del contexto de gin donde se guarda el identificador.
+const requestIDKey = "request.id"
+
+// RequestID asigna un identificador a cada petición y lo devuelve en la
+// cabecera X-Request-ID, para poder relacionar una respuesta de error con
+// el registro del servidor.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > 64 {
+			id = newRequestID()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// CurrentRequestID devuelve el identificador asignado por RequestID.
+func CurrentRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ErrorResponse es el cuerpo de todas las respuestas de error de la API.
+type ErrorResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// Errors responde los errores que los handlers y middlewares registran con
+// c.Error. Si hay varios se usa el último, y si el handler ya escribió la
+// respuesta no se hace nada. Los errores de dominio se traducen con su tipo
+// y su mensaje; los inesperados se registran en el log con el identificador
+// de la petición y al cliente solo le llega un mensaje genérico.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		last := c.Errors.Last()
+		if last == nil || c.Writer.Written() {
+			return
+		}
+		appErr := apperror.From(last.Err)
+		requestID := CurrentRequestID(c)
+		if appErr.Kind == apperror.KindInternal {
+			log.Printf("[%s] %s %s: %v", requestID, c.Request.Method, c.Request.URL.Path, last.Err)
+		}
+		c.AbortWithStatusJSON(appErr.Kind.Status(), ErrorResponse{
+			Code:      appErr.Code,
+			Message:   appErr.Message,
+			Details:   appErr.Details,
+			RequestID: requestID,
+		})
+	}
+}
+
+// abortWithError registra err para que lo responda Errors y corta la cadena.
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
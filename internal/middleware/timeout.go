@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout limita el tiempo que una petición puede ocupar la base de datos:
+// el contexto de la petición vence a los d, y GORM cancela la consulta en
+// curso (revirtiendo su transacción y liberando los bloqueos) cuando vence o
+// cuando el cliente se desconecta. Con d <= 0 no se aplica límite.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if d <= 0 {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"fmt"
+	"io"
+
+	"gorm.io/gorm"
+)
+
+// RunCommand ejecuta el subcomando "migrate up|down|status" de las
+// herramientas de línea de comandos y escribe el resultado en out.
+func RunCommand(db *gorm.DB, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: migrate up|down|status")
+	}
+	switch args[0] {
+	case "up":
+		applied, err := Up(db)
+		for _, m := range applied {
+			fmt.Fprintf(out, "Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "Database schema is up to date")
+		}
+	case "down":
+		reverted, err := Down(db)
+		if err != nil {
+			return err
+		}
+		if reverted == nil {
+			fmt.Fprintln(out, "No migrations to revert")
+			return nil
+		}
+		fmt.Fprintf(out, "Reverted %04d_%s\n", reverted.Version, reverted.Name)
+	case "status":
+		statuses, err := GetStatus(db)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(out, "%04d_%-40s %s\n", s.Version, s.Name, applied)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q (use up, down or status)", args[0])
+	}
+	return nil
+}
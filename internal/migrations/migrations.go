@@ -0,0 +1,268 @@
+// Package migrations aplica los cambios de esquema versionados. Cada versión
+// es un par de archivos sql/NNNN_nombre.up.sql y sql/NNNN_nombre.down.sql
+// incluidos en el binario; las versiones aplicadas se registran en la tabla
+// schema_migrations.
+//
+// Para cambiar el esquema se agrega una nueva versión; nunca se editan las
+// versiones ya publicadas.
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// ErrSchemaOutdated indica que la base de datos no está en la versión que
+// espera el binario.
+var ErrSchemaOutdated = errors.New("el esquema de la base de datos no está actualizado")
+
+// lockID identifica el advisory lock que impide correr dos migraciones a la vez.
+const lockID = 724311
+
+// Migration es una versión del esquema.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status es el estado de una versión en la base de datos.
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"appliedAt"` // nil si está pendiente
+}
+
+// schemaMigration es la fila de schema_migrations.
+type schemaMigration struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load devuelve las migraciones incluidas en el binario, ordenadas por versión.
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("nombre de migración inválido: %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := files.ReadFile(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("la versión %d tiene dos nombres: %s y %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("a la migración %04d_%s le falta el archivo up o down", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up aplica todas las migraciones pendientes, cada una en su transacción, y
+// devuelve las que aplicó.
+func Up(db *gorm.DB) ([]Migration, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+				return err
+			}
+			// Se vuelve a consultar con el lock tomado por si otro proceso la aplicó.
+			var count int64
+			if err := tx.Model(&schemaMigration{}).Where("version = ?", m.Version).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+			if err := tx.Exec(m.Up).Error; err != nil {
+				return fmt.Errorf("migración %04d_%s: %w", m.Version, m.Name, err)
+			}
+			applied = append(applied, m)
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return applied, err
+		}
+	}
+	return applied, nil
+}
+
+// Down revierte la última migración aplicada y la devuelve (nil si no había ninguna).
+func Down(db *gorm.DB) (*Migration, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	var reverted *Migration
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+			return err
+		}
+		var last schemaMigration
+		err := tx.Order("version desc").First(&last).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for i := range migrations {
+			if migrations[i].Version == last.Version {
+				reverted = &migrations[i]
+			}
+		}
+		if reverted == nil {
+			return fmt.Errorf("la versión aplicada %04d_%s no existe en este binario", last.Version, last.Name)
+		}
+		if err := tx.Exec(reverted.Down).Error; err != nil {
+			return fmt.Errorf("migración %04d_%s: %w", reverted.Version, reverted.Name, err)
+		}
+		return tx.Delete(&schemaMigration{}, last.Version).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reverted, nil
+}
+
+// GetStatus devuelve todas las versiones conocidas y las aplicadas en la base
+// de datos, incluidas las que este binario no conoce.
+func GetStatus(db *gorm.DB) ([]Status, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(migrations))
+	known := map[int]bool{}
+	for _, m := range migrations {
+		known[m.Version] = true
+		status := Status{Version: m.Version, Name: m.Name}
+		if row, ok := applied[m.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for version, row := range applied {
+		if !known[version] {
+			appliedAt := row.AppliedAt
+			statuses = append(statuses, Status{Version: version, Name: row.Name, AppliedAt: &appliedAt})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// EnsureCurrent devuelve ErrSchemaOutdated si hay migraciones pendientes o si
+// la base de datos tiene versiones que este binario no conoce. La API lo
+// verifica al arrancar en lugar de modificar el esquema por su cuenta.
+func EnsureCurrent(db *gorm.DB) error {
+	statuses, err := GetStatus(db)
+	if err != nil {
+		return err
+	}
+	migrations, err := Load()
+	if err != nil {
+		return err
+	}
+	latest := 0
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+
+	pending := 0
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			pending++
+		} else if s.Version > latest {
+			return fmt.Errorf("%w: la base de datos tiene la versión %04d_%s, más nueva que este binario", ErrSchemaOutdated, s.Version, s.Name)
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w: %d migraciones pendientes", ErrSchemaOutdated, pending)
+	}
+	return nil
+}
+
+func ensureTable(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint PRIMARY KEY,
+	name text NOT NULL,
+	applied_at timestamptz NOT NULL
+)`).Error
+}
+
+func appliedVersions(db *gorm.DB) (map[int]schemaMigration, error) {
+	applied := map[int]schemaMigration{}
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return applied, nil
+	}
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
@@ -0,0 +1,20 @@
+DROP TABLE IF EXISTS "user_roles";
+DROP TABLE IF EXISTS "role_permissions";
+DROP TABLE IF EXISTS "audit_entries";
+DROP TABLE IF EXISTS "revoked_tokens";
+DROP TABLE IF EXISTS "users";
+DROP TABLE IF EXISTS "roles";
+DROP TABLE IF EXISTS "permissions";
+DROP TABLE IF EXISTS "procurement_thresholds";
+DROP TABLE IF EXISTS "tax_unit_values";
+DROP TABLE IF EXISTS "exchange_rates";
+DROP TABLE IF EXISTS "tax_rates";
+DROP TABLE IF EXISTS "system_counters";
+DROP TABLE IF EXISTS "order_status_history";
+DROP TABLE IF EXISTS "quotations";
+DROP TABLE IF EXISTS "order_items";
+DROP TABLE IF EXISTS "orders";
+DROP TABLE IF EXISTS "providers";
+DROP TABLE IF EXISTS "officials";
+DROP TABLE IF EXISTS "positions";
+DROP TABLE IF EXISTS "units";
@@ -0,0 +1,309 @@
+-- Esquema inicial: reproduce lo que creaba AutoMigrate. Usa IF NOT EXISTS
+-- para poder adoptar las bases de datos creadas antes de las migraciones.
+
+CREATE TABLE IF NOT EXISTS "units" (
+    "id" bigserial,
+    "created_at" bigint,
+    "updated_at" bigint,
+    "deleted_at" timestamptz,
+    "name" text NOT NULL,
+    "is_active" boolean DEFAULT true,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_units_name_active" ON "units" ("name") WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_units_deleted_at" ON "units" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "positions" (
+    "id" bigserial,
+    "created_at" bigint,
+    "updated_at" bigint,
+    "deleted_at" timestamptz,
+    "name" text NOT NULL,
+    "is_active" boolean DEFAULT true,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_positions_name_active" ON "positions" ("name") WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_positions_deleted_at" ON "positions" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "officials" (
+    "id" bigserial,
+    "created_at" bigint,
+    "updated_at" bigint,
+    "deleted_at" timestamptz,
+    "full_name" text NOT NULL,
+    "is_active" boolean DEFAULT true,
+    "unit_id" bigint,
+    "position_id" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_officials_unit" FOREIGN KEY ("unit_id") REFERENCES "units"("id"),
+    CONSTRAINT "fk_officials_position" FOREIGN KEY ("position_id") REFERENCES "positions"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_officials_deleted_at" ON "officials" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "providers" (
+    "id" bigserial,
+    "created_at" bigint,
+    "updated_at" bigint,
+    "deleted_at" timestamptz,
+    "name" text NOT NULL,
+    "rif" text,
+    "address" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_providers_rif_active" ON "providers" ("rif") WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_providers_deleted_at" ON "providers" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "orders" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "memo_date" timestamptz,
+    "memo_number" text,
+    "unit_id" bigint,
+    "official_id" bigint,
+    "requesting_unit" text,
+    "responsible_official" text,
+    "concept" text,
+    "provider_id" bigint,
+    "provider" text,
+    "document_type" text,
+    "budget_number" text,
+    "budget_date" timestamptz,
+    "base_amount" numeric(18,2),
+    "iva_amount" numeric(18,2),
+    "total_amount" numeric(18,2),
+    "tax_exempt" boolean,
+    "applied_tax_rate" numeric(5,2),
+    "currency" text DEFAULT 'VES',
+    "exchange_rate_id" bigint,
+    "exchange_rate" numeric(18,6),
+    "exchange_rate_date" date,
+    "base_amount_ves" numeric(18,2),
+    "iva_amount_ves" numeric(18,2),
+    "total_amount_ves" numeric(18,2),
+    "delivery_time" text,
+    "offer_quality" text,
+    "account_point_date" timestamptz,
+    "price_inquiry_type" text,
+    "contract_type" text DEFAULT 'BIENES',
+    "subject" text,
+    "synthesis" text,
+    "programmatic_category" text,
+    "uel" text,
+    "tax_unit_value" numeric(18,2),
+    "amount_ut" numeric(18,2),
+    "required_modality" text,
+    "modality_mismatch" boolean,
+    "status" text DEFAULT 'Borrador',
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_orders_unit" FOREIGN KEY ("unit_id") REFERENCES "units"("id"),
+    CONSTRAINT "fk_orders_official" FOREIGN KEY ("official_id") REFERENCES "officials"("id"),
+    CONSTRAINT "fk_orders_provider_ref" FOREIGN KEY ("provider_id") REFERENCES "providers"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_orders_provider_id" ON "orders" ("provider_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_official_id" ON "orders" ("official_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_unit_id" ON "orders" ("unit_id");
+CREATE INDEX IF NOT EXISTS "idx_orders_deleted_at" ON "orders" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "order_items" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "order_id" bigint NOT NULL,
+    "description" text NOT NULL,
+    "quantity" numeric(12,3) NOT NULL,
+    "unit_of_measure" text,
+    "unit_price" numeric(18,2) NOT NULL,
+    "tax_code" text DEFAULT 'GENERAL',
+    "tax_exempt" boolean,
+    "tax_rate" numeric(5,2),
+    "line_total" numeric(18,2),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_orders_items" FOREIGN KEY ("order_id") REFERENCES "orders"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_order_items_order_id" ON "order_items" ("order_id");
+CREATE INDEX IF NOT EXISTS "idx_order_items_deleted_at" ON "order_items" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "quotations" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "order_id" bigint NOT NULL,
+    "provider_id" bigint NOT NULL,
+    "document_type" text,
+    "budget_number" text,
+    "budget_date" timestamptz,
+    "currency" text DEFAULT 'VES',
+    "amount" numeric(18,2),
+    "exchange_rate" numeric(18,6),
+    "amount_ves" numeric(18,2),
+    "delivery_time" text,
+    "delivery_days" bigint,
+    "offer_quality" text,
+    "quality_score" bigint,
+    "notes" text,
+    "selected" boolean,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_quotations_provider" FOREIGN KEY ("provider_id") REFERENCES "providers"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_quotations_order_provider_active" ON "quotations" ("order_id","provider_id") WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_quotations_deleted_at" ON "quotations" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "order_status_history" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "order_id" bigint NOT NULL,
+    "from_status" text NOT NULL,
+    "to_status" text NOT NULL,
+    "changed_by" text NOT NULL,
+    "reason" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_order_status_history_order_id" ON "order_status_history" ("order_id");
+
+CREATE TABLE IF NOT EXISTS "system_counters" (
+    "id" bigserial,
+    "document_type" text NOT NULL,
+    "current_year" bigint NOT NULL,
+    "last_sequence" bigint NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_system_counters_document_type" ON "system_counters" ("document_type");
+
+CREATE TABLE IF NOT EXISTS "tax_rates" (
+    "id" bigserial,
+    "created_at" bigint,
+    "updated_at" bigint,
+    "deleted_at" timestamptz,
+    "code" text NOT NULL,
+    "name" text NOT NULL,
+    "rate" numeric(5,2) NOT NULL,
+    "valid_from" timestamptz NOT NULL,
+    "valid_to" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_tax_rates_code" ON "tax_rates" ("code");
+CREATE INDEX IF NOT EXISTS "idx_tax_rates_deleted_at" ON "tax_rates" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "exchange_rates" (
+    "id" bigserial,
+    "created_at" bigint,
+    "updated_at" bigint,
+    "deleted_at" timestamptz,
+    "date" date NOT NULL,
+    "currency" text NOT NULL,
+    "rate" numeric(18,6) NOT NULL,
+    "source" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_exchange_rates_date_currency_active" ON "exchange_rates" ("date","currency") WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_exchange_rates_deleted_at" ON "exchange_rates" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "tax_unit_values" (
+    "id" bigserial,
+    "created_at" bigint,
+    "updated_at" bigint,
+    "deleted_at" timestamptz,
+    "valid_from" date NOT NULL,
+    "value" numeric(18,2) NOT NULL,
+    "gazette" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tax_unit_values_valid_from_active" ON "tax_unit_values" ("valid_from") WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_tax_unit_values_deleted_at" ON "tax_unit_values" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "procurement_thresholds" (
+    "id" bigserial,
+    "created_at" bigint,
+    "updated_at" bigint,
+    "deleted_at" timestamptz,
+    "contract_type" text NOT NULL,
+    "modality" text NOT NULL,
+    "max_ut" numeric(18,2),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_procurement_thresholds_contract_type" ON "procurement_thresholds" ("contract_type");
+CREATE INDEX IF NOT EXISTS "idx_procurement_thresholds_deleted_at" ON "procurement_thresholds" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "permissions" (
+    "id" bigserial,
+    "code" text NOT NULL,
+    "description" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_permissions_code" ON "permissions" ("code");
+
+CREATE TABLE IF NOT EXISTS "roles" (
+    "id" bigserial,
+    "created_at" bigint,
+    "updated_at" bigint,
+    "deleted_at" timestamptz,
+    "code" text NOT NULL,
+    "name" text NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_roles_code_active" ON "roles" ("code") WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_roles_deleted_at" ON "roles" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "created_at" bigint,
+    "updated_at" bigint,
+    "deleted_at" timestamptz,
+    "username" text NOT NULL,
+    "password_hash" text NOT NULL,
+    "is_active" boolean DEFAULT true,
+    "official_id" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_official" FOREIGN KEY ("official_id") REFERENCES "officials"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_official_id" ON "users" ("official_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username_active" ON "users" ("username") WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "revoked_tokens" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "jti" text NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_revoked_tokens_expires_at" ON "revoked_tokens" ("expires_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_revoked_tokens_jti" ON "revoked_tokens" ("jti");
+
+CREATE TABLE IF NOT EXISTS "audit_entries" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "user_id" bigint,
+    "username" text,
+    "ip" text,
+    "entity_type" text NOT NULL,
+    "entity_id" bigint NOT NULL,
+    "action" text NOT NULL,
+    "before" jsonb,
+    "after" jsonb,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_entity" ON "audit_entries" ("entity_type","entity_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_username" ON "audit_entries" ("username");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_user_id" ON "audit_entries" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_created_at" ON "audit_entries" ("created_at");
+
+CREATE TABLE IF NOT EXISTS "role_permissions" (
+    "role_id" bigint,
+    "permission_id" bigint,
+    PRIMARY KEY ("role_id","permission_id"),
+    CONSTRAINT "fk_role_permissions_role" FOREIGN KEY ("role_id") REFERENCES "roles"("id"),
+    CONSTRAINT "fk_role_permissions_permission" FOREIGN KEY ("permission_id") REFERENCES "permissions"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "user_roles" (
+    "user_id" bigint,
+    "role_id" bigint,
+    PRIMARY KEY ("user_id","role_id"),
+    CONSTRAINT "fk_user_roles_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_user_roles_role" FOREIGN KEY ("role_id") REFERENCES "roles"("id")
+);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "idx_system_counters_type_year";
+-- Solo puede volver a haber un contador por tipo: se conserva el del último año.
+DELETE FROM "system_counters" c
+USING "system_counters" newer
+WHERE newer.document_type = c.document_type AND newer.current_year > c.current_year;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_system_counters_document_type" ON "system_counters" ("document_type");
+
+DROP TABLE IF EXISTS "fiscal_years";
@@ -0,0 +1,25 @@
+-- Ejercicios fiscales y un contador por tipo de documento y año (antes el
+-- índice único sobre document_type impedía tener dos años del mismo tipo).
+CREATE TABLE "fiscal_years" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "year" bigint NOT NULL,
+    "status" text NOT NULL DEFAULT 'ABIERTO',
+    "closed_at" timestamptz,
+    "closed_by" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_fiscal_years_year" ON "fiscal_years" ("year");
+
+DROP INDEX IF EXISTS "idx_system_counters_document_type";
+CREATE UNIQUE INDEX "idx_system_counters_type_year" ON "system_counters" ("document_type","current_year");
+
+-- Los años que ya tienen contadores quedan registrados: el más reciente
+-- abierto y los anteriores cerrados.
+INSERT INTO "fiscal_years" ("created_at", "year", "status", "closed_at", "closed_by")
+SELECT now(), y.current_year,
+       CASE WHEN y.current_year = m.max_year THEN 'ABIERTO' ELSE 'CERRADO' END,
+       CASE WHEN y.current_year = m.max_year THEN NULL ELSE now() END,
+       CASE WHEN y.current_year = m.max_year THEN NULL ELSE 'migración' END
+FROM (SELECT DISTINCT current_year FROM "system_counters") y
+CROSS JOIN (SELECT max(current_year) AS max_year FROM "system_counters") m;
@@ -0,0 +1,12 @@
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "purchase_order_number";
+ALTER TABLE "orders" DROP COLUMN IF EXISTS "account_point_number";
+
+-- Solo se conservan los contadores globales de cada ejercicio.
+DELETE FROM "system_counters" WHERE "unit_id" <> 0 OR "current_year" = 0;
+DROP INDEX IF EXISTS "idx_system_counters_type_year_unit";
+ALTER TABLE "system_counters" DROP COLUMN IF EXISTS "unit_id";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_system_counters_type_year" ON "system_counters" ("document_type","current_year");
+
+ALTER TABLE "units" DROP COLUMN IF EXISTS "code";
+
+DROP TABLE IF EXISTS "document_types";
@@ -0,0 +1,29 @@
+-- Configuración de la numeración por tipo de documento. Los tipos del flujo
+-- de las órdenes los registra la API al arrancar con el formato histórico.
+CREATE TABLE "document_types" (
+    "id" bigserial,
+    "created_at" bigint,
+    "updated_at" bigint,
+    "deleted_at" timestamptz,
+    "code" text NOT NULL,
+    "name" text NOT NULL,
+    "prefix" text,
+    "pattern" text NOT NULL,
+    "scope" text NOT NULL DEFAULT 'GLOBAL',
+    "reset_policy" text NOT NULL DEFAULT 'YEARLY',
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_document_types_code_active" ON "document_types" ("code") WHERE deleted_at IS NULL;
+CREATE INDEX "idx_document_types_deleted_at" ON "document_types" ("deleted_at");
+
+-- Abreviatura de la unidad para las series por unidad.
+ALTER TABLE "units" ADD COLUMN "code" text;
+
+-- Un contador por tipo, año y unidad (0 = serie global; año 0 = no se reinicia).
+ALTER TABLE "system_counters" ADD COLUMN "unit_id" bigint NOT NULL DEFAULT 0;
+DROP INDEX IF EXISTS "idx_system_counters_type_year";
+CREATE UNIQUE INDEX "idx_system_counters_type_year_unit" ON "system_counters" ("document_type","current_year","unit_id");
+
+-- Números propios del punto de cuenta y de la orden de compra.
+ALTER TABLE "orders" ADD COLUMN "account_point_number" text;
+ALTER TABLE "orders" ADD COLUMN "purchase_order_number" text;
@@ -0,0 +1,46 @@
+-- Registro de cada correlativo consumido: asignado, anulado o fallido.
+CREATE TABLE "issued_numbers" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "document_type" text NOT NULL,
+    "year" bigint NOT NULL,
+    "unit_id" bigint NOT NULL DEFAULT 0,
+    "sequence" bigint NOT NULL,
+    "number" text NOT NULL,
+    "status" text NOT NULL,
+    "order_id" bigint,
+    "issued_by" text,
+    "reason" text,
+    "voided_by" text,
+    "voided_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_issued_numbers_series_sequence" ON "issued_numbers" ("document_type","year","unit_id","sequence");
+CREATE INDEX "idx_issued_numbers_status" ON "issued_numbers" ("status");
+CREATE INDEX "idx_issued_numbers_order_id" ON "issued_numbers" ("order_id");
+
+-- Los memos ya emitidos (con el formato histórico MEMO-AÑO-SECUENCIA) quedan
+-- asignados a su orden, incluidas las eliminadas.
+INSERT INTO "issued_numbers" ("created_at", "document_type", "year", "unit_id", "sequence", "number", "status", "order_id", "issued_by")
+SELECT o.created_at, 'MEMO', o.parts[1]::bigint, 0, o.parts[2]::bigint, o.memo_number, 'USED', o.id, 'migración'
+FROM (
+    SELECT id, created_at, memo_number, regexp_match(memo_number, '^MEMO-(\d{4})-(\d+)$') AS parts
+    FROM "orders"
+) o
+WHERE o.parts IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- El resto de los números que los contadores ya consumieron se perdió en
+-- inserciones fallidas: se registran como fallidos para que puedan anularse.
+INSERT INTO "issued_numbers" ("created_at", "document_type", "year", "unit_id", "sequence", "number", "status", "issued_by", "reason")
+SELECT now(), c.document_type, c.current_year, c.unit_id, s.seq,
+       c.document_type || '-' || c.current_year || '-' || lpad(s.seq::text, greatest(5, length(s.seq::text)), '0'),
+       'FAILED', 'migración',
+       'Número consumido sin documento antes de llevar el registro de números emitidos'
+FROM "system_counters" c
+CROSS JOIN LATERAL generate_series(1, c.last_sequence) AS s(seq)
+WHERE NOT EXISTS (
+    SELECT 1 FROM "issued_numbers" i
+    WHERE i.document_type = c.document_type AND i.year = c.current_year
+      AND i.unit_id = c.unit_id AND i.sequence = s.seq
+);
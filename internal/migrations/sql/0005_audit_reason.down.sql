@@ -0,0 +1 @@
+ALTER TABLE "audit_entries" DROP COLUMN IF EXISTS "reason";
@@ -0,0 +1,2 @@
+-- Justificación de los cambios que la exigen (ajustes manuales de contadores).
+ALTER TABLE "audit_entries" ADD COLUMN "reason" text;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "idx_units_code_active";
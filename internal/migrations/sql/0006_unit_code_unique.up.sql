@@ -0,0 +1,5 @@
+-- El código de la unidad aparece en los números de las series por unidad:
+-- dos unidades activas con el mismo código emitirían números repetidos.
+-- Si ya hay códigos repetidos la migración falla y deben corregirse a mano.
+UPDATE "units" SET "code" = upper(btrim("code")) WHERE "code" IS NOT NULL;
+CREATE UNIQUE INDEX "idx_units_code_active" ON "units" ("code") WHERE deleted_at IS NULL AND code <> '';
@@ -0,0 +1,3 @@
+-- Los proveedores inactivos se conservan para el histórico, pero no pueden
+-- asignarse a órdenes ni cotizaciones nuevas.
+ALTER TABLE "providers" ADD COLUMN "is_active" boolean DEFAULT true;
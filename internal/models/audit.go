@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Acciones registradas en la bitácora de auditoría.
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditEntry registra un alta, modificación o baja de una entidad auditada,
+// con quién la hizo y los valores antes y después del cambio.
+type AuditEntry struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+
+	UserID   *uint  `gorm:"index" json:"userId"` // nil para procesos del sistema
+	Username string `gorm:"index" json:"username"`
+	IP       string `json:"ip"`
+
+	EntityType string `gorm:"index:idx_audit_entity;not null" json:"entityType"` // Ej: "Provider", "Order"
+	EntityID   uint   `gorm:"index:idx_audit_entity;not null" json:"entityId"`
+	Action     string `gorm:"not null" json:"action"`
+	// Reason es la justificación indicada para el cambio, si la hubo.
+	Reason string `gorm:"type:text" json:"reason,omitempty"`
+
+	// En las modificaciones solo se guardan las columnas que cambiaron; en
+	// las altas y bajas, el registro completo.
+	Before JSON `gorm:"type:jsonb" json:"before"`
+	After  JSON `gorm:"type:jsonb" json:"after"`
+}
+
+// JSON guarda un documento JSON ya serializado en una columna jsonb.
+type JSON []byte
+
+// Value implementa driver.Valuer.
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+// Scan implementa sql.Scanner.
+func (j *JSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append(JSON(nil), v...)
+	case string:
+		*j = JSON(v)
+	default:
+		return fmt.Errorf("tipo no soportado para JSON: %T", value)
+	}
+	return nil
+}
+
+// MarshalJSON devuelve el documento tal cual, o null si está vacío.
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
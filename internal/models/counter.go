@@ -3,10 +3,13 @@ package models
 import "gorm.io/gorm"
 
 // SystemCounter almacena el estado de los contadores de documentos del sistema.
+// Hay un contador por tipo de documento, ejercicio fiscal y unidad. Las
+// series globales usan UnitID 0 y las que no se reinician usan el año 0.
 type SystemCounter struct {
-	ID           uint   `gorm:"primarykey"`
-	DocumentType string `gorm:"uniqueIndex;not null"` // Ej: "MEMO", "ORDER", "ACCOUNT_POINT"
-	CurrentYear  int    `gorm:"not null"`
-	LastSequence uint   `gorm:"not null"`
+	ID           uint   `gorm:"primarykey" json:"id"`
+	DocumentType string `gorm:"uniqueIndex:idx_system_counters_type_year_unit;not null" json:"documentType"` // Ej: "MEMO", "ORDER", "ACCOUNT_POINT"
+	CurrentYear  int    `gorm:"uniqueIndex:idx_system_counters_type_year_unit;not null" json:"year"`
+	UnitID       uint   `gorm:"uniqueIndex:idx_system_counters_type_year_unit;not null;default:0" json:"unitId"`
+	LastSequence uint   `gorm:"not null" json:"lastSequence"`
 	gorm.Model   `gorm:"-"` // Para no incluir los campos por defecto de gorm si no los necesitas
-}
\ No newline at end of file
+}
@@ -0,0 +1,42 @@
+package models
+
+import "gorm.io/gorm"
+
+// Códigos de los tipos de documento con correlativo propio.
+const (
+	DocumentTypeMemo         = "MEMO"
+	DocumentTypeOrder        = "ORDER"
+	DocumentTypeAccountPoint = "ACCOUNT_POINT"
+)
+
+// Alcance de la serie de un tipo de documento.
+const (
+	NumberingScopeGlobal = "GLOBAL" // Una sola serie para todo el organismo
+	NumberingScopeUnit   = "UNIT"   // Una serie por unidad solicitante
+)
+
+// Política de reinicio de la serie.
+const (
+	ResetPolicyYearly = "YEARLY" // Vuelve a 1 en cada ejercicio fiscal
+	ResetPolicyNever  = "NEVER"  // Continúa entre ejercicios
+)
+
+// DefaultNumberPattern es el formato histórico: PREFIJO-AÑO-SECUENCIA.
+const DefaultNumberPattern = "{PREFIX}-{YYYY}-{SEQ:5}"
+
+// DocumentType configura cómo se numera un tipo de documento. El patrón
+// admite los tokens {PREFIX}, {UNIT}, {YYYY}, {YY}, {SEQ} y {SEQ:n}, donde
+// n es la cantidad mínima de dígitos de la secuencia.
+type DocumentType struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt int64          `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Code        string `gorm:"uniqueIndex:idx_document_types_code_active,where:deleted_at IS NULL;not null" json:"code"` // Ej: "MEMO"
+	Name        string `gorm:"not null" json:"name"`
+	Prefix      string `json:"prefix"`
+	Pattern     string `gorm:"not null" json:"pattern"`
+	Scope       string `gorm:"not null;default:GLOBAL" json:"scope"`
+	ResetPolicy string `gorm:"not null;default:YEARLY" json:"resetPolicy"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Monedas admitidas en las órdenes. Los montos se registran en la moneda de
+// la cotización y se convierten a bolívares con la tasa guardada en la orden.
+const (
+	CurrencyVES = "VES"
+	CurrencyUSD = "USD"
+	CurrencyEUR = "EUR"
+)
+
+// ExchangeRate es la tasa de cambio oficial de una moneda en una fecha,
+// expresada en bolívares por unidad de la moneda. La cargan los analistas.
+type ExchangeRate struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt int64          `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Date     time.Time       `gorm:"type:date;uniqueIndex:idx_exchange_rates_date_currency_active,where:deleted_at IS NULL;not null" json:"date"`
+	Currency string          `gorm:"uniqueIndex:idx_exchange_rates_date_currency_active,where:deleted_at IS NULL;not null" json:"currency"`
+	Rate     decimal.Decimal `gorm:"type:numeric(18,6);not null" json:"rate"`
+	Source   string          `json:"source"` // Ej: "BCV"
+}
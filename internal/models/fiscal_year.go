@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Estados de un ejercicio fiscal.
+const (
+	FiscalYearOpen   = "ABIERTO"
+	FiscalYearClosed = "CERRADO"
+)
+
+// FiscalYear es el ejercicio fiscal bajo el que se emiten los correlativos.
+// Solo se emiten números en el ejercicio abierto; al cerrarlo se congelan sus
+// contadores y se abre el siguiente.
+type FiscalYear struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	Year     int        `gorm:"uniqueIndex;not null" json:"year"`
+	Status   string     `gorm:"not null;default:'ABIERTO'" json:"status"`
+	ClosedAt *time.Time `json:"closedAt"`
+	ClosedBy string     `json:"closedBy"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Estados de un número emitido.
+const (
+	// IssuedNumberUsed: el número está asignado a un documento.
+	IssuedNumberUsed = "USED"
+	// IssuedNumberVoided: el número se anuló con una justificación.
+	IssuedNumberVoided = "VOIDED"
+	// IssuedNumberFailed: el contador avanzó sin que se guardara el documento.
+	// Hoy la numeración comparte la transacción con el documento, así que solo
+	// aparecen en los números consumidos antes de llevar este registro.
+	IssuedNumberFailed = "FAILED"
+)
+
+// IssuedNumber es el registro de cada correlativo consumido, para que todo
+// número de la serie quede justificado ante los auditores. Year y UnitID
+// identifican la serie igual que en SystemCounter.
+type IssuedNumber struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	DocumentType string `gorm:"uniqueIndex:idx_issued_numbers_series_sequence;not null" json:"documentType"`
+	Year         int    `gorm:"uniqueIndex:idx_issued_numbers_series_sequence;not null" json:"year"`
+	UnitID       uint   `gorm:"uniqueIndex:idx_issued_numbers_series_sequence;not null;default:0" json:"unitId"`
+	Sequence     uint   `gorm:"uniqueIndex:idx_issued_numbers_series_sequence;not null" json:"sequence"`
+	Number       string `gorm:"not null" json:"number"`
+	Status       string `gorm:"index;not null" json:"status"`
+	OrderID      *uint  `gorm:"index" json:"orderId"`
+	IssuedBy     string `json:"issuedBy"`
+
+	// Datos de la anulación (o la explicación de un número fallido).
+	Reason   string     `gorm:"type:text" json:"reason,omitempty"`
+	VoidedBy string     `json:"voidedBy,omitempty"`
+	VoidedAt *time.Time `json:"voidedAt,omitempty"`
+}
@@ -2,6 +2,9 @@ package models
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/money"
 	"gorm.io/gorm"
 )
 
@@ -14,32 +17,64 @@ type Order struct {
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // Para soft delete
 
 	// --- Paso 1: Requisición ---
-	MemoDate            time.Time `json:"memoDate"`
-	MemoNumber          string    `json:"memoNumber"`
-	RequestingUnit      string    `json:"requestingUnit"`
-	ResponsibleOfficial string    `json:"responsibleOfficial"`
-	Concept             string    `gorm:"type:text" json:"concept"`
+	MemoDate   time.Time `json:"memoDate"`
+	MemoNumber string    `json:"memoNumber"`
+	UnitID     *uint     `gorm:"index" json:"unitId"`
+	Unit       *Unit     `json:"unit,omitempty"`
+	OfficialID *uint     `gorm:"index" json:"officialId"`
+	Official   *Official `json:"official,omitempty"`
+	// Copias de los nombres al momento de registrar la orden, para que un
+	// cambio posterior en los datos maestros no altere órdenes históricas.
+	RequestingUnit      string `json:"requestingUnit"`
+	ResponsibleOfficial string `json:"responsibleOfficial"`
+	Concept             string `gorm:"type:text" json:"concept"`
 
 	// --- Paso 2: Cotización ---
-	Provider      string    `json:"provider"`
-	DocumentType  string    `json:"documentType"`
-	BudgetNumber  string    `json:"budgetNumber"`
-	BudgetDate    time.Time `json:"budgetDate"`
-	BaseAmount    float64   `json:"baseAmount"`
-	IvaAmount     float64   `json:"ivaAmount"` // Lo calcularemos en el backend
-	TotalAmount   float64   `json:"totalAmount"` // (Base + IVA)
-	DeliveryTime  string    `json:"deliveryTime"`
-	OfferQuality  string    `json:"offerQuality"`
+	ProviderID   *uint       `gorm:"index" json:"providerId"`
+	ProviderRef  *Provider   `gorm:"foreignKey:ProviderID" json:"providerRef,omitempty"`
+	Provider     string      `json:"provider"` // Nombre del proveedor al momento de asignarlo
+	DocumentType string      `json:"documentType"`
+	BudgetNumber string      `json:"budgetNumber"`
+	BudgetDate   time.Time   `json:"budgetDate"`
+	BaseAmount   money.Money `json:"baseAmount"`  // Suma de las líneas, calculada en el backend
+	IvaAmount    money.Money `json:"ivaAmount"`   // IVA por alícuota, calculado en el backend
+	TotalAmount  money.Money `json:"totalAmount"` // (Base + IVA)
+	TaxExempt    bool        `json:"taxExempt"`   // Orden exenta de IVA en su totalidad
+	// Alícuota general vigente a la fecha del presupuesto; se guarda para que la
+	// orden conserve su cálculo aunque la alícuota cambie después.
+	AppliedTaxRate decimal.Decimal `gorm:"type:numeric(5,2)" json:"appliedTaxRate"`
+
+	// Moneda de la cotización y equivalente en bolívares. La tasa usada se
+	// guarda en la orden para que el punto de cuenta sea reproducible.
+	Currency         string          `gorm:"default:'VES'" json:"currency"`
+	ExchangeRateID   *uint           `json:"exchangeRateId"`
+	ExchangeRate     decimal.Decimal `gorm:"type:numeric(18,6)" json:"exchangeRate"` // Bs. por unidad de la moneda
+	ExchangeRateDate *time.Time      `gorm:"type:date" json:"exchangeRateDate"`
+	BaseAmountVES    money.Money     `json:"baseAmountVes"`
+	IvaAmountVES     money.Money     `json:"ivaAmountVes"`
+	TotalAmountVES   money.Money     `json:"totalAmountVes"`
+
+	DeliveryTime string `json:"deliveryTime"`
+	OfferQuality string `json:"offerQuality"`
 
 	// --- Paso 3: Punto de Cuenta ---
 	AccountPointDate     time.Time `gorm:"autoCreateTime" json:"accountPointDate"` // Se genera automáticamente
-	PriceInquiryType     string    `json:"priceInquiryType"`
+	AccountPointNumber   string    `json:"accountPointNumber"`                     // Se asigna al pasar a Punto de Cuenta
+	PriceInquiryType     string    `json:"priceInquiryType"`                       // Modalidad elegida (ver procurement.go)
+	ContractType         string    `gorm:"default:'BIENES'" json:"contractType"`   // BIENES, SERVICIOS u OBRAS
 	Subject              string    `json:"subject"`
 	Synthesis            string    `gorm:"type:text" json:"synthesis"`
 	ProgrammaticCategory string    `json:"programmaticCategory"`
 	UEL                  string    `json:"uel"`
+	// Modalidad que corresponde según el total en UT, calculada en el backend.
+	// ModalityMismatch marca las órdenes cuya modalidad elegida no coincide.
+	TaxUnitValue     money.Money     `json:"taxUnitValue"`
+	AmountUT         decimal.Decimal `gorm:"type:numeric(18,2)" json:"amountUt"`
+	RequiredModality string          `json:"requiredModality"`
+	ModalityMismatch bool            `json:"modalityMismatch"`
 
 	// --- Paso 4: Orden ---
-	// Ítems se manejarán en una tabla separada más adelante.
-	Status string `gorm:"default:'En Proceso'" json:"status"`
-}
\ No newline at end of file
+	PurchaseOrderNumber string      `json:"purchaseOrderNumber"` // Se asigna al emitir la orden
+	Items               []OrderItem `gorm:"constraint:OnDelete:CASCADE" json:"items"`
+	Status              string      `gorm:"default:'Borrador'" json:"status"` // Ver order_status.go
+}
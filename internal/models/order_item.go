@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// OrderItem representa una línea (ítem) de una orden de compra o servicio.
+type OrderItem struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	OrderID uint `gorm:"index;not null" json:"orderId"`
+
+	Description   string          `gorm:"type:text;not null" json:"description"`
+	Quantity      decimal.Decimal `gorm:"type:numeric(12,3);not null" json:"quantity"`
+	UnitOfMeasure string          `json:"unitOfMeasure"` // Ej: "UND", "KG", "SERVICIO"
+	UnitPrice     money.Money     `gorm:"not null" json:"unitPrice"`
+	TaxCode       string          `gorm:"default:'GENERAL'" json:"taxCode"` // Alícuota a aplicar (ver TaxRate)
+	TaxExempt     bool            `json:"taxExempt"`                        // Ítem exento de IVA
+	TaxRate       decimal.Decimal `gorm:"type:numeric(5,2)" json:"taxRate"` // Porcentaje aplicado, calculado en el backend
+	LineTotal     money.Money     `json:"lineTotal"`                        // Cantidad * Precio Unitario (sin IVA), calculado en el backend
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Estados del ciclo de vida de una orden.
+const (
+	OrderStatusDraft        = "Borrador"
+	OrderStatusRequisition  = "Requisición"
+	OrderStatusQuotation    = "Cotización"
+	OrderStatusAccountPoint = "Punto de Cuenta"
+	OrderStatusApproved     = "Aprobada"
+	OrderStatusIssued       = "Orden Emitida"
+	OrderStatusReceived     = "Recibida"
+	OrderStatusPaid         = "Pagada"
+	OrderStatusCancelled    = "Anulada"
+
+	// OrderStatusLegacy es el estado que tenían las órdenes creadas antes del
+	// flujo de estados. Se trata igual que un borrador.
+	OrderStatusLegacy = "En Proceso"
+)
+
+// OrderStatusHistory registra cada cambio de estado de una orden
+// (quién lo hizo, cuándo y por qué) para la auditoría.
+type OrderStatusHistory struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	OrderID    uint   `gorm:"index;not null" json:"orderId"`
+	FromStatus string `gorm:"not null" json:"fromStatus"`
+	ToStatus   string `gorm:"not null" json:"toStatus"`
+	ChangedBy  string `gorm:"not null" json:"changedBy"`
+	Reason     string `gorm:"type:text" json:"reason"`
+}
+
+// TableName evita el plural irregular que generaría GORM ("order_status_histories").
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
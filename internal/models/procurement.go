@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// Modalidades de selección de contratistas de la Ley de Contrataciones Públicas.
+const (
+	ModalityPriceInquiry = "Consulta de Precios"
+	ModalityClosedTender = "Concurso Cerrado"
+	ModalityOpenTender   = "Concurso Abierto"
+	// La contratación directa procede por supuestos de excepción y no por monto,
+	// por eso se admite con cualquier total.
+	ModalityDirect = "Contratación Directa"
+)
+
+// Tipos de contratación; cada uno tiene sus propios umbrales.
+const (
+	ContractTypeGoods    = "BIENES"
+	ContractTypeServices = "SERVICIOS"
+	ContractTypeWorks    = "OBRAS"
+)
+
+// TaxUnitValue es el valor de la unidad tributaria (UT) en bolívares a partir
+// de una fecha. Se conserva el histórico para recalcular órdenes viejas.
+type TaxUnitValue struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt int64          `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	ValidFrom time.Time   `gorm:"type:date;uniqueIndex:idx_tax_unit_values_valid_from_active,where:deleted_at IS NULL;not null" json:"validFrom"`
+	Value     money.Money `gorm:"not null" json:"value"` // Bs. por UT
+	Gazette   string      `json:"gazette"`               // Gaceta Oficial que la publica
+}
+
+// ProcurementThreshold indica hasta cuántas UT procede una modalidad para un
+// tipo de contratación. Para un total dado se aplica el umbral con el menor
+// límite que lo cubra; el umbral sin límite (MaxUT nil) cubre el resto.
+type ProcurementThreshold struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt int64          `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	ContractType string           `gorm:"index;not null" json:"contractType"`
+	Modality     string           `gorm:"not null" json:"modality"`
+	MaxUT        *decimal.Decimal `gorm:"type:numeric(18,2)" json:"maxUt"` // nil = sin límite superior
+}
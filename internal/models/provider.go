@@ -9,7 +9,8 @@ type Provider struct {
 	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
-	Name    string `gorm:"not null" json:"name"`
-	RIF     string `gorm:"uniqueIndex:idx_providers_rif_active,where:deleted_at IS NULL" json:"rif"`
-	Address string `json:"address"`
+	Name     string `gorm:"not null" json:"name"`
+	RIF      string `gorm:"uniqueIndex:idx_providers_rif_active,where:deleted_at IS NULL" json:"rif"`
+	Address  string `json:"address"`
+	IsActive bool   `gorm:"default:true" json:"isActive"`
 }
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/money"
+	"gorm.io/gorm"
+)
+
+// Quotation es una de las cotizaciones recibidas para una orden (consulta de
+// precios). Se comparan en el cuadro comparativo y la seleccionada completa
+// los datos del paso 2 de la orden.
+type Quotation struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Un proveedor cotiza una sola vez por orden.
+	OrderID    uint      `gorm:"uniqueIndex:idx_quotations_order_provider_active,where:deleted_at IS NULL;not null" json:"orderId"`
+	ProviderID uint      `gorm:"uniqueIndex:idx_quotations_order_provider_active,where:deleted_at IS NULL;not null" json:"providerId"`
+	Provider   *Provider `json:"provider,omitempty"`
+
+	DocumentType string    `json:"documentType"`
+	BudgetNumber string    `json:"budgetNumber"`
+	BudgetDate   time.Time `json:"budgetDate"`
+
+	Currency     string          `gorm:"default:'VES'" json:"currency"`
+	Amount       money.Money     `json:"amount"`                                 // Monto ofertado sin IVA, en la moneda de la cotización
+	ExchangeRate decimal.Decimal `gorm:"type:numeric(18,6)" json:"exchangeRate"` // Tasa usada para comparar en bolívares
+	AmountVES    money.Money     `json:"amountVes"`                              // Monto en bolívares, calculado en el backend
+
+	DeliveryTime string `json:"deliveryTime"` // Texto tal como lo ofrece el proveedor
+	DeliveryDays int    `json:"deliveryDays"` // Días hábiles, usados para puntuar
+	OfferQuality string `json:"offerQuality"`
+	QualityScore int    `json:"qualityScore"` // 1 a 5, asignado por el analista
+	Notes        string `gorm:"type:text" json:"notes"`
+
+	Selected bool `json:"selected"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "gorm.io/gorm"
+
+// Permisos. Las rutas de lectura exigen el permiso ":read" del grupo y las de
+// escritura el ":write"; los servicios vuelven a verificarlos.
+const (
+	PermOrdersRead  = "orders:read"
+	PermOrdersWrite = "orders:write"
+	// PermOrdersApprove permite aprobar órdenes de la unidad del funcionario del usuario.
+	PermOrdersApprove = "orders:approve"
+	// PermOrdersApproveAll permite aprobar órdenes de cualquier unidad.
+	PermOrdersApproveAll = "orders:approve-all"
+	PermProvidersRead    = "providers:read"
+	PermProvidersWrite   = "providers:write"
+	PermMasterDataRead   = "master-data:read"
+	PermMasterDataWrite  = "master-data:write"
+	PermAdminRead        = "admin:read"
+	PermAdminWrite       = "admin:write"
+)
+
+// Roles predefinidos que se crean al iniciar si no hay roles registrados.
+const (
+	RoleAdmin    = "ADMINISTRADOR"
+	RoleAnalyst  = "ANALISTA_COMPRAS"
+	RoleUnitHead = "JEFE_UNIDAD"
+	RoleAuditor  = "AUDITOR"
+)
+
+// Permission es un permiso asignable a los roles.
+type Permission struct {
+	ID          uint   `gorm:"primarykey" json:"id"`
+	Code        string `gorm:"uniqueIndex;not null" json:"code"`
+	Description string `json:"description"`
+}
+
+// Role agrupa permisos; un usuario puede tener varios roles.
+type Role struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt int64          `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Code        string       `gorm:"uniqueIndex:idx_roles_code_active,where:deleted_at IS NULL;not null" json:"code"`
+	Name        string       `gorm:"not null" json:"name"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Códigos de alícuota de IVA.
+const (
+	TaxCodeGeneral = "GENERAL"
+	TaxCodeReduced = "REDUCIDA"
+)
+
+// TaxRate representa una alícuota de IVA vigente en un rango de fechas.
+// Cuando el SENIAT cambia una alícuota se cierra el rango anterior (ValidTo)
+// y se registra uno nuevo, de modo que las órdenes viejas conservan su cálculo.
+type TaxRate struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt int64          `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Code      string          `gorm:"index;not null" json:"code"` // Ej: "GENERAL", "REDUCIDA"
+	Name      string          `gorm:"not null" json:"name"`
+	Rate      decimal.Decimal `gorm:"type:numeric(5,2);not null" json:"rate"` // Porcentaje (ej. 16)
+	ValidFrom time.Time       `gorm:"not null" json:"validFrom"`
+	ValidTo   *time.Time      `json:"validTo"` // nil = vigente sin fecha de cierre
+}
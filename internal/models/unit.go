@@ -10,5 +10,6 @@ type Unit struct {
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
 	Name     string `gorm:"uniqueIndex:idx_units_name_active,where:deleted_at IS NULL;not null" json:"name"`
+	Code     string `gorm:"uniqueIndex:idx_units_code_active,where:deleted_at IS NULL AND code <> ''" json:"code"` // Abreviatura usada en la numeración de documentos (ej. "SG")
 	IsActive bool   `gorm:"default:true" json:"isActive"`
 }
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// User es una cuenta de acceso al sistema, vinculada al funcionario que la usa.
+type User struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt int64          `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt int64          `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Username     string `gorm:"uniqueIndex:idx_users_username_active,where:deleted_at IS NULL;not null" json:"username"`
+	PasswordHash string `gorm:"not null" json:"-"` // bcrypt
+	IsActive     bool   `gorm:"default:true" json:"isActive"`
+
+	OfficialID *uint     `gorm:"index" json:"officialId"`
+	Official   *Official `json:"official,omitempty"`
+	Roles      []Role    `gorm:"many2many:user_roles" json:"roles"`
+}
+
+// RevokedToken registra los tokens invalidados antes de vencer (cierre de
+// sesión o refresh ya usado). Se pueden borrar una vez vencidos.
+type RevokedToken struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	JTI       string    `gorm:"uniqueIndex;not null" json:"jti"`
+	ExpiresAt time.Time `gorm:"index;not null" json:"expiresAt"`
+}
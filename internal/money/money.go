@@ -0,0 +1,131 @@
+// Package money define el tipo Money para montos en bolívares (u otra moneda)
+// con precisión exacta de céntimos, almacenado como numeric en PostgreSQL.
+//
+// Reglas de redondeo:
+//   - Todo Money tiene exactamente dos decimales; al construirlo se redondea
+//     al céntimo más cercano y las mitades se alejan de cero (0,005 → 0,01).
+//   - El total de una línea es Round(cantidad × precio unitario).
+//   - El IVA se calcula por alícuota sobre la suma de las bases de esa
+//     alícuota: Round(base × tasa / 100). Así el IVA coincide con el que se
+//     imprime en el documento, donde se muestra una base por alícuota.
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Scale es la cantidad de decimales de un monto (céntimos).
+const Scale = 2
+
+var hundred = decimal.NewFromInt(100)
+
+// Money es un monto con dos decimales exactos.
+type Money struct {
+	d decimal.Decimal
+}
+
+// Zero es el monto 0,00.
+var Zero = Money{}
+
+// FromDecimal redondea d a céntimos.
+func FromDecimal(d decimal.Decimal) Money {
+	return Money{d: d.Round(Scale)}
+}
+
+// FromCents construye un monto a partir de céntimos (ej. 123456 → 1234,56).
+func FromCents(cents int64) Money {
+	return Money{d: decimal.New(cents, -Scale)}
+}
+
+// Parse interpreta un monto escrito con punto decimal (ej. "1234.56").
+func Parse(s string) (Money, error) {
+	d, err := decimal.NewFromString(strings.TrimSpace(s))
+	if err != nil {
+		return Zero, fmt.Errorf("monto inválido %q: %w", s, err)
+	}
+	return FromDecimal(d), nil
+}
+
+// MustParse es como Parse pero entra en pánico si el texto no es válido.
+// Solo para constantes en el código.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Decimal devuelve el valor como decimal.Decimal para operaciones intermedias.
+func (m Money) Decimal() decimal.Decimal { return m.d }
+
+func (m Money) Add(o Money) Money { return Money{d: m.d.Add(o.d)} }
+func (m Money) Sub(o Money) Money { return Money{d: m.d.Sub(o.d)} }
+
+// Mul multiplica por una cantidad y redondea a céntimos.
+func (m Money) Mul(q decimal.Decimal) Money { return FromDecimal(m.d.Mul(q)) }
+
+// Percent calcula rate% del monto y redondea a céntimos (ej. IVA al 16%).
+func (m Money) Percent(rate decimal.Decimal) Money {
+	return FromDecimal(m.d.Mul(rate).Div(hundred))
+}
+
+func (m Money) Cmp(o Money) int              { return m.d.Cmp(o.d) }
+func (m Money) Equal(o Money) bool           { return m.d.Equal(o.d) }
+func (m Money) LessThan(o Money) bool        { return m.d.LessThan(o.d) }
+func (m Money) GreaterThan(o Money) bool     { return m.d.GreaterThan(o.d) }
+func (m Money) IsZero() bool                 { return m.d.IsZero() }
+func (m Money) IsNegative() bool             { return m.d.IsNegative() }
+func (m Money) Float64() float64             { f, _ := m.d.Float64(); return f }
+func (m Money) String() string               { return m.d.StringFixed(Scale) }
+func (Money) GormDataType() string           { return "numeric(18,2)" }
+func (m Money) MarshalText() ([]byte, error) { return []byte(m.String()), nil }
+
+// MarshalJSON serializa el monto como string con dos decimales ("1234.50"),
+// para que el cliente no lo convierta a coma flotante sin darse cuenta.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// UnmarshalJSON acepta tanto "1234.56" como 1234.56.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*m = Zero
+		return nil
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan implementa sql.Scanner.
+func (m *Money) Scan(value interface{}) error {
+	var d decimal.Decimal
+	if err := d.Scan(value); err != nil {
+		return err
+	}
+	*m = FromDecimal(d)
+	return nil
+}
+
+// Value implementa driver.Valuer; se guarda como texto para no perder precisión.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Sum suma una lista de montos.
+func Sum(amounts ...Money) Money {
+	total := Zero
+	for _, a := range amounts {
+		total = total.Add(a)
+	}
+	return total
+}
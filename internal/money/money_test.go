@@ -0,0 +1,152 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   bool
+	}{
+		{input: "1234.56", want: "1234.56"},
+		{input: " 1234.5 ", want: "1234.50"},
+		{input: "7", want: "7.00"},
+		{input: "-0.5", want: "-0.50"},
+		// Más de dos decimales: se redondea al céntimo, las mitades lejos de cero.
+		{input: "1.004", want: "1.00"},
+		{input: "1.005", want: "1.01"},
+		{input: "-1.005", want: "-1.01"},
+		{input: "0.0049999", want: "0.00"},
+		{input: "", err: true},
+		{input: "12,50", err: true},
+		{input: "abc", err: true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if tt.err {
+			if err == nil {
+				t.Errorf("Parse(%q) = %s, want error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		amount   string
+		quantity string
+		want     string
+	}{
+		{"12.50", "3", "37.50"},
+		{"19.99", "0.5", "10.00"}, // 9.995
+		{"0.01", "0.5", "0.01"},   // 0.005
+		{"0.01", "0.4", "0.00"},   // 0.004
+		{"1234.56", "1.5", "1851.84"},
+		{"-0.01", "0.5", "-0.01"},
+	}
+	for _, tt := range tests {
+		got := MustParse(tt.amount).Mul(decimal.RequireFromString(tt.quantity))
+		if got.String() != tt.want {
+			t.Errorf("%s × %s = %s, want %s", tt.amount, tt.quantity, got, tt.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		amount string
+		rate   string
+		want   string
+	}{
+		{"1234.56", "16", "197.53"}, // 197.5296
+		{"123.45", "10", "12.35"},   // 12.345
+		{"100.00", "16", "16.00"},
+		{"0.03", "16", "0.00"}, // 0.0048
+		{"1000.00", "0", "0.00"},
+		{"99.99", "8", "8.00"}, // 7.9992
+	}
+	for _, tt := range tests {
+		got := MustParse(tt.amount).Percent(decimal.RequireFromString(tt.rate))
+		if got.String() != tt.want {
+			t.Errorf("%s%% de %s = %s, want %s", tt.rate, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		amount Money
+		want   string
+	}{
+		{Zero, "0.00"},
+		{FromCents(123456), "1234.56"},
+		{FromCents(-5), "-0.05"},
+		{FromDecimal(decimal.RequireFromString("10.1")), "10.10"},
+		{Sum(MustParse("0.10"), MustParse("0.20")), "0.30"},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Total Money `json:"total"`
+	}{MustParse("1234.5")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"total":"1234.50"}`; string(data) != want {
+		t.Errorf("json.Marshal = %s, want %s", data, want)
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   bool
+	}{
+		{input: `"1234.56"`, want: "1234.56"},
+		{input: `1234.56`, want: "1234.56"},
+		{input: `1234.5`, want: "1234.50"},
+		{input: `"0.125"`, want: "0.13"},
+		{input: `0.125`, want: "0.13"},
+		{input: `null`, want: "0.00"},
+		{input: `""`, want: "0.00"},
+		{input: `"12,50"`, err: true},
+		{input: `true`, err: true},
+	}
+	for _, tt := range tests {
+		var m Money
+		err := json.Unmarshal([]byte(tt.input), &m)
+		if tt.err {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %s, want error", tt.input, m)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unmarshal(%s) unexpected error: %v", tt.input, err)
+			continue
+		}
+		if m.String() != tt.want {
+			t.Errorf("Unmarshal(%s) = %s, want %s", tt.input, m, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/toor/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditFilter reúne los criterios de búsqueda de la bitácora de auditoría.
+// Los campos vacíos (nil o "") no filtran.
+type AuditFilter struct {
+	EntityType string
+	EntityID   *uint
+	Action     string
+	Username   string
+	UserID     *uint
+	From       *time.Time
+	To         *time.Time // Inclusivo: se incluye todo el día
+	Page       int
+	Limit      int
+}
+
+// Normalize completa la paginación con valores por defecto y dentro de los límites.
+func (f *AuditFilter) Normalize() {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.Limit < 1 {
+		f.Limit = DefaultPageSize
+	}
+	if f.Limit > MaxPageSize {
+		f.Limit = MaxPageSize
+	}
+}
+
+func (f *AuditFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.EntityType != "" {
+		db = db.Where("entity_type = ?", f.EntityType)
+	}
+	if f.EntityID != nil {
+		db = db.Where("entity_id = ?", *f.EntityID)
+	}
+	if f.Action != "" {
+		db = db.Where("action = ?", f.Action)
+	}
+	if f.Username != "" {
+		db = db.Where("username = ?", f.Username)
+	}
+	if f.UserID != nil {
+		db = db.Where("user_id = ?", *f.UserID)
+	}
+	if f.From != nil {
+		db = db.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		db = db.Where("created_at < ?", f.To.AddDate(0, 0, 1))
+	}
+	return db
+}
+
+type AuditRepository interface {
+	GetAll(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, int64, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// GetAll devuelve las entradas que cumplen el filtro, de la más reciente a la más antigua.
+func (r *auditRepository) GetAll(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, int64, error) {
+	filter.Normalize()
+
+	var total int64
+	if err := filter.apply(r.db.WithContext(ctx).Model(&models.AuditEntry{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.AuditEntry
+	err := filter.apply(r.db.WithContext(ctx)).
+		Order("created_at desc").Order("id desc").
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
@@ -1,17 +1,58 @@
 package repository
 
 import (
+	"context"
 	"errors"
-	"fmt"
+	"time"
 
+	"github.com/toor/backend/internal/apperror"
 	"github.com/toor/backend/internal/models"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+var (
+	// ErrFiscalYearClosed indica que el ejercicio fiscal ya fue cerrado.
+	ErrFiscalYearClosed = apperror.Conflict("FISCAL_YEAR_CLOSED", "el ejercicio fiscal está cerrado")
+	// ErrNoOpenFiscalYear indica que no hay un ejercicio abierto en el que emitir números.
+	ErrNoOpenFiscalYear = apperror.Conflict("NO_OPEN_FISCAL_YEAR", "no hay un ejercicio fiscal abierto")
+)
+
 type CounterRepository interface {
-	GetNextSequence(docType string, year int) (uint, error)
-	ResetAllCounters(newYear int) error
+	// GetNextSequence consume el siguiente número de la serie del tipo de
+	// documento y la unidad (0 para las series globales) y devuelve el año del
+	// ejercicio abierto junto con la secuencia. Si yearly es false la serie
+	// no se reinicia entre ejercicios.
+	GetNextSequence(ctx context.Context, docType string, unitID uint, yearly bool) (int, uint, error)
+	// LockFiscalYear bloquea el ejercicio para modificarlo; debe usarse
+	// dentro de una transacción (ver UnitOfWork).
+	LockFiscalYear(ctx context.Context, year int) (*models.FiscalYear, error)
+	SaveFiscalYear(ctx context.Context, fiscalYear *models.FiscalYear) error
+	// OpenFiscalYear registra el ejercicio como abierto si todavía no existe.
+	OpenFiscalYear(ctx context.Context, year int) error
+	// EnsureCounters crea en cero los contadores del año y la unidad (0 para
+	// las series globales) que todavía no existen.
+	EnsureCounters(ctx context.Context, year int, docTypes []string, unitID uint) error
+	GetFiscalYears(ctx context.Context) ([]models.FiscalYear, error)
+	GetAll(ctx context.Context) ([]models.SystemCounter, error)
+	// FindCounters devuelve los contadores del tipo y año indicados; los
+	// filtros vacíos (docType "" o year nil) no se aplican.
+	FindCounters(ctx context.Context, docType string, year *int) ([]models.SystemCounter, error)
+
+	// LockCounter bloquea el contador de la serie en el ejercicio abierto
+	// (creándolo en cero si no existe) y devuelve también el año del ejercicio.
+	LockCounter(ctx context.Context, docType string, unitID uint, yearly bool) (*models.SystemCounter, int, error)
+	// PeekCounter es como LockCounter pero sin bloquear ni crear registros.
+	PeekCounter(ctx context.Context, docType string, unitID uint, yearly bool) (*models.SystemCounter, int, error)
+	SaveCounter(ctx context.Context, counter *models.SystemCounter) error
+
+	// Registro de números emitidos
+	SaveIssuedNumber(ctx context.Context, entry *models.IssuedNumber) error
+	CreateIssuedNumbers(ctx context.Context, entries []models.IssuedNumber) error
+	FindIssuedNumber(ctx context.Context, docType string, year int, unitID, sequence uint) (*models.IssuedNumber, error)
+	FindIssuedNumbers(ctx context.Context, docType string, year *int) ([]models.IssuedNumber, error)
+	// IsOrderActive indica si la orden existe y no está anulada.
+	IsOrderActive(ctx context.Context, orderID uint) (bool, error)
 }
 
 type counterRepository struct {
@@ -23,54 +64,241 @@ func NewCounterRepository(db *gorm.DB) CounterRepository {
 }
 
 // GetNextSequence obtiene el siguiente número de forma segura (transaccional).
-func (r *counterRepository) GetNextSequence(docType string, year int) (uint, error) {
-	var counter models.SystemCounter
+// El año sale del ejercicio abierto y no del reloj, de modo que los primeros
+// documentos de enero siguen en la serie del ejercicio anterior hasta su cierre.
+func (r *counterRepository) GetNextSequence(ctx context.Context, docType string, unitID uint, yearly bool) (int, uint, error) {
+	var year int
 	var nextSequence uint
 
-	err := r.db.Transaction(func(tx *gorm.DB) error {
-		// Bloquea la fila para evitar que dos peticiones incrementen el contador al mismo tiempo
-		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			Where("document_type = ? AND current_year = ?", docType, year).
-			First(&counter).Error
-
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			// Si no existe un contador para este tipo y año, lo creamos
-			counter = models.SystemCounter{
-				DocumentType: docType,
-				CurrentYear:  year,
-				LastSequence: 0,
-			}
-			if createErr := tx.Create(&counter).Error; createErr != nil {
-				return createErr
-			}
-		} else if err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		counter, fiscalYear, err := lockCounter(tx, docType, unitID, yearly)
+		if err != nil {
 			return err
 		}
 
 		// Incrementa la secuencia y la guarda
 		counter.LastSequence++
-		if saveErr := tx.Save(&counter).Error; saveErr != nil {
+		if saveErr := tx.Save(counter).Error; saveErr != nil {
 			return saveErr
 		}
 
+		year = fiscalYear
 		nextSequence = counter.LastSequence
 		return nil
 	})
 
 	if err != nil {
-		return 0, err
+		return 0, 0, err
+	}
+
+	return year, nextSequence, nil
+}
+
+// LockCounter debe llamarse dentro de una transacción (ver UnitOfWork): el
+// bloqueo dura hasta que esta termina.
+func (r *counterRepository) LockCounter(ctx context.Context, docType string, unitID uint, yearly bool) (*models.SystemCounter, int, error) {
+	return lockCounter(r.db.WithContext(ctx), docType, unitID, yearly)
+}
+
+// lockCounter bloquea el contador de la serie en el ejercicio abierto y lo
+// crea en cero si todavía no existe. Devuelve también el año del ejercicio.
+func lockCounter(tx *gorm.DB, docType string, unitID uint, yearly bool) (*models.SystemCounter, int, error) {
+	// El bloqueo compartido hace esperar a un cierre en curso del ejercicio.
+	fiscalYear, err := openFiscalYear(tx)
+	if err != nil {
+		return nil, 0, err
+	}
+	counterYear := 0
+	if yearly {
+		counterYear = fiscalYear.Year
+	}
+
+	// Bloquea la fila para evitar que dos peticiones incrementen el contador al mismo tiempo
+	var counter models.SystemCounter
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("document_type = ? AND current_year = ? AND unit_id = ?", docType, counterYear, unitID).
+		First(&counter).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Si no existe un contador para esta serie, lo creamos
+		counter = models.SystemCounter{
+			DocumentType: docType,
+			CurrentYear:  counterYear,
+			UnitID:       unitID,
+			LastSequence: 0,
+		}
+		if createErr := tx.Create(&counter).Error; createErr != nil {
+			return nil, 0, createErr
+		}
+	} else if err != nil {
+		return nil, 0, err
+	}
+	return &counter, fiscalYear.Year, nil
+}
+
+// PeekCounter lee el contador de la serie en el ejercicio abierto sin
+// bloquearlo ni crear nada. Si la serie no tiene contador devuelve uno en cero.
+func (r *counterRepository) PeekCounter(ctx context.Context, docType string, unitID uint, yearly bool) (*models.SystemCounter, int, error) {
+	var fiscalYear models.FiscalYear
+	err := r.db.WithContext(ctx).Where("status = ?", models.FiscalYearOpen).Order("year desc").First(&fiscalYear).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		var count int64
+		if err := r.db.WithContext(ctx).Model(&models.FiscalYear{}).Count(&count).Error; err != nil {
+			return nil, 0, err
+		}
+		if count > 0 {
+			return nil, 0, ErrNoOpenFiscalYear
+		}
+		// El primer número emitido abrirá el ejercicio del año en curso.
+		fiscalYear.Year = time.Now().Year()
+	} else if err != nil {
+		return nil, 0, err
+	}
+
+	counter := models.SystemCounter{DocumentType: docType, UnitID: unitID}
+	if yearly {
+		counter.CurrentYear = fiscalYear.Year
+	}
+	err = r.db.WithContext(ctx).
+		Where("document_type = ? AND current_year = ? AND unit_id = ?", docType, counter.CurrentYear, unitID).
+		First(&counter).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+	return &counter, fiscalYear.Year, nil
+}
+
+func (r *counterRepository) SaveCounter(ctx context.Context, counter *models.SystemCounter) error {
+	return r.db.WithContext(ctx).Save(counter).Error
+}
+
+// openFiscalYear devuelve el ejercicio abierto más reciente con un bloqueo
+// compartido. Si todavía no hay ningún ejercicio, abre el del año en curso.
+func openFiscalYear(tx *gorm.DB) (*models.FiscalYear, error) {
+	var fiscalYear models.FiscalYear
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("status = ?", models.FiscalYearOpen).
+		Order("year desc").
+		First(&fiscalYear).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return &fiscalYear, err
+	}
+
+	var count int64
+	if err := tx.Model(&models.FiscalYear{}).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrNoOpenFiscalYear
+	}
+	fiscalYear = models.FiscalYear{Year: time.Now().Year(), Status: models.FiscalYearOpen}
+	err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&fiscalYear).Error
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("year = ?", fiscalYear.Year).
+		First(&fiscalYear).Error
+	return &fiscalYear, err
+}
+
+func (r *counterRepository) LockFiscalYear(ctx context.Context, year int) (*models.FiscalYear, error) {
+	var fiscalYear models.FiscalYear
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("year = ?", year).
+		First(&fiscalYear).Error
+	if err != nil {
+		return nil, notFound(err, ErrFiscalYearNotFound)
 	}
+	return &fiscalYear, nil
+}
+
+func (r *counterRepository) SaveFiscalYear(ctx context.Context, fiscalYear *models.FiscalYear) error {
+	return r.db.WithContext(ctx).Save(fiscalYear).Error
+}
 
-	return nextSequence, nil
+func (r *counterRepository) OpenFiscalYear(ctx context.Context, year int) error {
+	fiscalYear := models.FiscalYear{Year: year, Status: models.FiscalYearOpen}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&fiscalYear).Error
 }
 
-// ResetAllCounters actualiza el año y reinicia la secuencia para todos los contadores.
-func (r *counterRepository) ResetAllCounters(newYear int) error {
-	// Esto es una simplificación. Una lógica más robusta crearía los contadores del nuevo año si no existen.
-	// Por ahora, asumimos que se crean al primer uso.
-	// Esta función puede expandirse para "pre-crear" contadores para el nuevo año.
-	// Por simplicidad, el reinicio es implícito: cuando `GetNextSequence` sea llamado con un nuevo año, creará un nuevo registro.
-	// Dejamos esta función como un placeholder para lógica de cierre más compleja si es necesaria.
-	fmt.Printf("Lógica de reinicio para el año %d activada. Los nuevos contadores se crearán bajo demanda.\n", newYear)
+func (r *counterRepository) EnsureCounters(ctx context.Context, year int, docTypes []string, unitID uint) error {
+	for _, docType := range docTypes {
+		counter := models.SystemCounter{DocumentType: docType, CurrentYear: year, UnitID: unitID}
+		err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&counter).Error
+		if err != nil {
+			return err
+		}
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *counterRepository) GetFiscalYears(ctx context.Context) ([]models.FiscalYear, error) {
+	var years []models.FiscalYear
+	err := r.db.WithContext(ctx).Order("year desc").Find(&years).Error
+	return years, err
+}
+
+// GetAll devuelve los contadores ordenados por año, tipo de documento y unidad.
+func (r *counterRepository) GetAll(ctx context.Context) ([]models.SystemCounter, error) {
+	var counters []models.SystemCounter
+	err := r.db.WithContext(ctx).Order("current_year desc, document_type asc, unit_id asc").Find(&counters).Error
+	return counters, err
+}
+
+func (r *counterRepository) FindCounters(ctx context.Context, docType string, year *int) ([]models.SystemCounter, error) {
+	query := r.db.WithContext(ctx).Order("document_type asc, current_year desc, unit_id asc")
+	if docType != "" {
+		query = query.Where("document_type = ?", docType)
+	}
+	if year != nil {
+		query = query.Where("current_year = ?", *year)
+	}
+	var counters []models.SystemCounter
+	err := query.Find(&counters).Error
+	return counters, err
+}
+
+// SaveIssuedNumber registra el número o guarda su anulación.
+func (r *counterRepository) SaveIssuedNumber(ctx context.Context, entry *models.IssuedNumber) error {
+	return r.db.WithContext(ctx).Save(entry).Error
+}
+
+func (r *counterRepository) CreateIssuedNumbers(ctx context.Context, entries []models.IssuedNumber) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(entries, 500).Error
+}
+
+func (r *counterRepository) FindIssuedNumber(ctx context.Context, docType string, year int, unitID, sequence uint) (*models.IssuedNumber, error) {
+	var entry models.IssuedNumber
+	err := r.db.WithContext(ctx).
+		Where("document_type = ? AND year = ? AND unit_id = ? AND sequence = ?", docType, year, unitID, sequence).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *counterRepository) FindIssuedNumbers(ctx context.Context, docType string, year *int) ([]models.IssuedNumber, error) {
+	query := r.db.WithContext(ctx).Order("document_type asc, year desc, unit_id asc, sequence asc")
+	if docType != "" {
+		query = query.Where("document_type = ?", docType)
+	}
+	if year != nil {
+		query = query.Where("year = ?", *year)
+	}
+	var entries []models.IssuedNumber
+	err := query.Find(&entries).Error
+	return entries, err
+}
+
+func (r *counterRepository) IsOrderActive(ctx context.Context, orderID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Order{}).
+		Where("id = ? AND status <> ?", orderID, models.OrderStatusCancelled).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/toor/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type DocumentTypeRepository interface {
+	Create(ctx context.Context, docType *models.DocumentType) error
+	GetAll(ctx context.Context) ([]models.DocumentType, error)
+	GetByID(ctx context.Context, id uint) (*models.DocumentType, error)
+	GetByCode(ctx context.Context, code string) (*models.DocumentType, error)
+	Update(ctx context.Context, docType *models.DocumentType) error
+	Delete(ctx context.Context, id uint) error
+	// HasIssuedNumbers indica si ya se emitió algún número del tipo.
+	HasIssuedNumbers(ctx context.Context, code string) (bool, error)
+}
+
+type documentTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentTypeRepository(db *gorm.DB) DocumentTypeRepository {
+	return &documentTypeRepository{db: db}
+}
+
+func (r *documentTypeRepository) Create(ctx context.Context, docType *models.DocumentType) error {
+	err := r.db.WithContext(ctx).Create(docType).Error
+	return duplicate(ctx, r.db, err, docType)
+}
+
+func (r *documentTypeRepository) GetAll(ctx context.Context) ([]models.DocumentType, error) {
+	var docTypes []models.DocumentType
+	err := r.db.WithContext(ctx).Order("code asc").Find(&docTypes).Error
+	return docTypes, err
+}
+
+func (r *documentTypeRepository) GetByID(ctx context.Context, id uint) (*models.DocumentType, error) {
+	var docType models.DocumentType
+	if err := r.db.WithContext(ctx).First(&docType, id).Error; err != nil {
+		return nil, notFound(err, ErrDocumentTypeNotFound)
+	}
+	return &docType, nil
+}
+
+func (r *documentTypeRepository) GetByCode(ctx context.Context, code string) (*models.DocumentType, error) {
+	var docType models.DocumentType
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&docType).Error; err != nil {
+		return nil, notFound(err, ErrDocumentTypeNotFound)
+	}
+	return &docType, nil
+}
+
+func (r *documentTypeRepository) Update(ctx context.Context, docType *models.DocumentType) error {
+	err := r.db.WithContext(ctx).Save(docType).Error
+	return duplicate(ctx, r.db, err, docType)
+}
+
+func (r *documentTypeRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.DocumentType{}, id).Error
+}
+
+func (r *documentTypeRepository) HasIssuedNumbers(ctx context.Context, code string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.SystemCounter{}).
+		Where("document_type = ? AND last_sequence > 0", code).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/toor/backend/internal/apperror"
+	"gorm.io/gorm"
+)
+
+// Errores de registro inexistente por entidad. Envuelven a
+// gorm.ErrRecordNotFound, de modo que errors.Is reconoce ambos.
+var (
+	ErrOrderNotFound        = apperror.NotFound("ORDER_NOT_FOUND", "la orden no existe")
+	ErrOrderItemNotFound    = apperror.NotFound("ORDER_ITEM_NOT_FOUND", "el ítem no existe en la orden")
+	ErrQuotationNotFound    = apperror.NotFound("QUOTATION_NOT_FOUND", "la cotización no existe en la orden")
+	ErrProviderNotFound     = apperror.NotFound("PROVIDER_NOT_FOUND", "el proveedor no existe")
+	ErrUnitNotFound         = apperror.NotFound("UNIT_NOT_FOUND", "la unidad no existe")
+	ErrOfficialNotFound     = apperror.NotFound("OFFICIAL_NOT_FOUND", "el funcionario no existe")
+	ErrDocumentTypeNotFound = apperror.NotFound("DOCUMENT_TYPE_NOT_FOUND", "el tipo de documento no existe")
+	ErrExchangeRateNotFound = apperror.NotFound("EXCHANGE_RATE_NOT_FOUND", "la tasa de cambio no existe")
+	ErrTaxRateNotFound      = apperror.NotFound("TAX_RATE_NOT_FOUND", "la alícuota no existe")
+	ErrTaxUnitNotFound      = apperror.NotFound("TAX_UNIT_NOT_FOUND", "el valor de la unidad tributaria no existe")
+	ErrThresholdNotFound    = apperror.NotFound("THRESHOLD_NOT_FOUND", "el umbral de contratación no existe")
+	ErrRoleNotFound         = apperror.NotFound("ROLE_NOT_FOUND", "el rol no existe")
+	ErrUserNotFound         = apperror.NotFound("USER_NOT_FOUND", "el usuario no existe")
+	ErrFiscalYearNotFound   = apperror.NotFound("FISCAL_YEAR_NOT_FOUND", "el ejercicio fiscal no existe")
+)
+
+// notFound reemplaza gorm.ErrRecordNotFound por el error de la entidad y
+// devuelve cualquier otro error sin cambios.
+func notFound(err error, target *apperror.Error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return target.Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/toor/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ExchangeRateRepository interface {
+	Create(ctx context.Context, rate *models.ExchangeRate) error
+	GetAll(ctx context.Context, currency string) ([]models.ExchangeRate, error)
+	GetByID(ctx context.Context, id uint) (*models.ExchangeRate, error)
+	Update(ctx context.Context, rate *models.ExchangeRate) error
+	Delete(ctx context.Context, id uint) error
+	// FindEffective devuelve la tasa más reciente de la moneda con fecha igual o anterior a date.
+	FindEffective(ctx context.Context, currency string, date time.Time) (*models.ExchangeRate, error)
+	// SaveAll crea o actualiza (por fecha y moneda) todas las tasas en una sola transacción.
+	SaveAll(ctx context.Context, rates []models.ExchangeRate) error
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+func (r *exchangeRateRepository) Create(ctx context.Context, rate *models.ExchangeRate) error {
+	err := r.db.WithContext(ctx).Create(rate).Error
+	return duplicate(ctx, r.db, err, rate)
+}
+
+func (r *exchangeRateRepository) GetAll(ctx context.Context, currency string) ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	query := r.db.WithContext(ctx).Order("date desc, currency asc")
+	if currency != "" {
+		query = query.Where("currency = ?", currency)
+	}
+	err := query.Find(&rates).Error
+	return rates, err
+}
+
+func (r *exchangeRateRepository) GetByID(ctx context.Context, id uint) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	if err := r.db.WithContext(ctx).First(&rate, id).Error; err != nil {
+		return nil, notFound(err, ErrExchangeRateNotFound)
+	}
+	return &rate, nil
+}
+
+func (r *exchangeRateRepository) Update(ctx context.Context, rate *models.ExchangeRate) error {
+	err := r.db.WithContext(ctx).Save(rate).Error
+	return duplicate(ctx, r.db, err, rate)
+}
+
+func (r *exchangeRateRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.ExchangeRate{}, id).Error
+}
+
+func (r *exchangeRateRepository) FindEffective(ctx context.Context, currency string, date time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.db.WithContext(ctx).
+		Where("currency = ? AND date <= ?", currency, date).
+		Order("date desc").
+		First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *exchangeRateRepository) SaveAll(ctx context.Context, rates []models.ExchangeRate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range rates {
+			var existing models.ExchangeRate
+			err := tx.Where("date = ? AND currency = ?", rates[i].Date, rates[i].Currency).First(&existing).Error
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				if err := tx.Create(&rates[i]).Error; err != nil {
+					return err
+				}
+			case err != nil:
+				return err
+			default:
+				existing.Rate = rates[i].Rate
+				existing.Source = rates[i].Source
+				if err := tx.Save(&existing).Error; err != nil {
+					return err
+				}
+				rates[i] = existing
+			}
+		}
+		return nil
+	})
+}
@@ -1,29 +1,35 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/toor/backend/internal/models"
 	"gorm.io/gorm"
 )
 
 type MasterDataRepository interface {
 	// Units
-	CreateUnit(unit *models.Unit) error
-	GetAllUnits() ([]models.Unit, error)
-	UpdateUnit(unit *models.Unit) error
-	DeleteUnit(id uint) error // <-- AÑADIR
+	CreateUnit(ctx context.Context, unit *models.Unit) error
+	GetAllUnits(ctx context.Context) ([]models.Unit, error)
+	GetUnitByID(ctx context.Context, id uint) (*models.Unit, error)
+	UpdateUnit(ctx context.Context, unit *models.Unit) error
+	DeleteUnit(ctx context.Context, id uint) error // <-- AÑADIR
 	// Positions
-	CreatePosition(pos *models.Position) error
-	GetAllPositions() ([]models.Position, error)
-	UpdatePosition(pos *models.Position) error
-	DeletePosition(id uint) error // <-- AÑADIR
+	CreatePosition(ctx context.Context, pos *models.Position) error
+	GetAllPositions(ctx context.Context) ([]models.Position, error)
+	UpdatePosition(ctx context.Context, pos *models.Position) error
+	DeletePosition(ctx context.Context, id uint) error // <-- AÑADIR
 	// Officials
-	CreateOfficial(off *models.Official) error
-	GetAllOfficials() ([]models.Official, error)
-	UpdateOfficial(off *models.Official) error
-	DeleteOfficial(id uint) error // <-- AÑADIR
+	CreateOfficial(ctx context.Context, off *models.Official) error
+	GetAllOfficials(ctx context.Context) ([]models.Official, error)
+	GetOfficialByID(ctx context.Context, id uint) (*models.Official, error)
+	UpdateOfficial(ctx context.Context, off *models.Official) error
+	DeleteOfficial(ctx context.Context, id uint) error // <-- AÑADIR
 
-	IsUnitInUse(unitID uint) (bool, error)
-	IsPositionInUse(positionID uint) (bool, error)
+	IsUnitInUse(ctx context.Context, unitID uint) (bool, error)
+	IsUnitReferencedByOrders(ctx context.Context, unitID uint) (bool, error)
+	IsPositionInUse(ctx context.Context, positionID uint) (bool, error)
+	IsOfficialReferencedByOrders(ctx context.Context, officialID uint) (bool, error)
 }
 
 type masterDataRepository struct {
@@ -35,66 +41,102 @@ func NewMasterDataRepository(db *gorm.DB) MasterDataRepository {
 }
 
 // Units
-func (r *masterDataRepository) CreateUnit(unit *models.Unit) error {
-	return r.db.Create(unit).Error
+func (r *masterDataRepository) CreateUnit(ctx context.Context, unit *models.Unit) error {
+	err := r.db.WithContext(ctx).Create(unit).Error
+	return duplicate(ctx, r.db, err, unit)
 }
-func (r *masterDataRepository) GetAllUnits() ([]models.Unit, error) {
+func (r *masterDataRepository) GetAllUnits(ctx context.Context) ([]models.Unit, error) {
 	var units []models.Unit
-	err := r.db.Order("name asc").Find(&units).Error
+	err := r.db.WithContext(ctx).Order("name asc").Find(&units).Error
 	return units, err
 }
-func (r *masterDataRepository) UpdateUnit(unit *models.Unit) error {
-	return r.db.Save(unit).Error
+func (r *masterDataRepository) GetUnitByID(ctx context.Context, id uint) (*models.Unit, error) {
+	var unit models.Unit
+	if err := r.db.WithContext(ctx).First(&unit, id).Error; err != nil {
+		return nil, notFound(err, ErrUnitNotFound)
+	}
+	return &unit, nil
+}
+func (r *masterDataRepository) UpdateUnit(ctx context.Context, unit *models.Unit) error {
+	err := r.db.WithContext(ctx).Save(unit).Error
+	return duplicate(ctx, r.db, err, unit)
 }
-func (r *masterDataRepository) DeleteUnit(id uint) error {
-	return r.db.Delete(&models.Unit{}, id).Error
+func (r *masterDataRepository) DeleteUnit(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Unit{}, id).Error
 }
 
 // Positions
-func (r *masterDataRepository) CreatePosition(pos *models.Position) error {
-	return r.db.Create(pos).Error
+func (r *masterDataRepository) CreatePosition(ctx context.Context, pos *models.Position) error {
+	err := r.db.WithContext(ctx).Create(pos).Error
+	return duplicate(ctx, r.db, err, pos)
 }
-func (r *masterDataRepository) GetAllPositions() ([]models.Position, error) {
+func (r *masterDataRepository) GetAllPositions(ctx context.Context) ([]models.Position, error) {
 	var positions []models.Position
-	err := r.db.Order("name asc").Find(&positions).Error
+	err := r.db.WithContext(ctx).Order("name asc").Find(&positions).Error
 	return positions, err
 }
-func (r *masterDataRepository) UpdatePosition(pos *models.Position) error {
-	return r.db.Save(pos).Error
+func (r *masterDataRepository) UpdatePosition(ctx context.Context, pos *models.Position) error {
+	err := r.db.WithContext(ctx).Save(pos).Error
+	return duplicate(ctx, r.db, err, pos)
 }
-func (r *masterDataRepository) DeletePosition(id uint) error {
-	return r.db.Delete(&models.Position{}, id).Error
+func (r *masterDataRepository) DeletePosition(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Position{}, id).Error
 }
 
 // Officials
-func (r *masterDataRepository) CreateOfficial(off *models.Official) error {
-	return r.db.Create(off).Error
+func (r *masterDataRepository) CreateOfficial(ctx context.Context, off *models.Official) error {
+	return r.db.WithContext(ctx).Create(off).Error
 }
-func (r *masterDataRepository) GetAllOfficials() ([]models.Official, error) {
+func (r *masterDataRepository) GetAllOfficials(ctx context.Context) ([]models.Official, error) {
 	var officials []models.Official
 	// Usamos Preload para traer los datos de Unit y Position
-	err := r.db.Preload("Unit").Preload("Position").Order("full_name asc").Find(&officials).Error
+	err := r.db.WithContext(ctx).Preload("Unit").Preload("Position").Order("full_name asc").Find(&officials).Error
 	return officials, err
 }
-func (r *masterDataRepository) UpdateOfficial(off *models.Official) error {
-	return r.db.Save(off).Error
+func (r *masterDataRepository) GetOfficialByID(ctx context.Context, id uint) (*models.Official, error) {
+	var official models.Official
+	if err := r.db.WithContext(ctx).Preload("Unit").Preload("Position").First(&official, id).Error; err != nil {
+		return nil, notFound(err, ErrOfficialNotFound)
+	}
+	return &official, nil
+}
+func (r *masterDataRepository) UpdateOfficial(ctx context.Context, off *models.Official) error {
+	return r.db.WithContext(ctx).Save(off).Error
 }
 
-func (r *masterDataRepository) DeleteOfficial(id uint) error {
-	return r.db.Delete(&models.Official{}, id).Error
+func (r *masterDataRepository) DeleteOfficial(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Official{}, id).Error
 }
-func (r *masterDataRepository) IsUnitInUse(unitID uint) (bool, error) {
+func (r *masterDataRepository) IsUnitInUse(ctx context.Context, unitID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Official{}).Where("unit_id = ?", unitID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *masterDataRepository) IsPositionInUse(ctx context.Context, positionID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Official{}).Where("position_id = ?", positionID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *masterDataRepository) IsUnitReferencedByOrders(ctx context.Context, unitID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.Official{}).Where("unit_id = ?", unitID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.Order{}).Where("unit_id = ?", unitID).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (r *masterDataRepository) IsPositionInUse(positionID uint) (bool, error) {
+func (r *masterDataRepository) IsOfficialReferencedByOrders(ctx context.Context, officialID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.Official{}).Where("position_id = ?", positionID).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&models.Order{}).Where("official_id = ?", officialID).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
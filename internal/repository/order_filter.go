@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/money"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// OrderFilter reúne los criterios de búsqueda, orden y paginación del listado de órdenes.
+// Los campos vacíos (nil o "") no filtran.
+type OrderFilter struct {
+	Statuses       []string
+	MemoDateFrom   *time.Time
+	MemoDateTo     *time.Time // Inclusivo: se incluye todo el día
+	BudgetDateFrom *time.Time
+	BudgetDateTo   *time.Time // Inclusivo: se incluye todo el día
+	ProviderID     *uint
+	Provider       string // Búsqueda parcial sobre el nombre guardado en la orden
+	UnitID         *uint
+	RequestingUnit string // Búsqueda parcial sobre el nombre guardado en la orden
+	MinAmount      *money.Money
+	MaxAmount      *money.Money
+	Search         string // Texto libre sobre el concepto y el asunto
+
+	// Sort es una lista separada por comas de claves de orden; un "-" inicial
+	// indica orden descendente (ej. "-memoDate,memoNumber").
+	Sort  string
+	Page  int
+	Limit int
+}
+
+// orderSortColumns son las claves de orden admitidas y su columna en la base de datos.
+var orderSortColumns = map[string]string{
+	"createdAt":   "created_at",
+	"memoDate":    "memo_date",
+	"memoNumber":  "memo_number",
+	"budgetDate":  "budget_date",
+	"totalAmount": "total_amount",
+	"status":      "status",
+}
+
+// IsValidOrderSort indica si todas las claves de sort son admitidas.
+func IsValidOrderSort(sort string) bool {
+	if sort == "" {
+		return true
+	}
+	for _, key := range strings.Split(sort, ",") {
+		if _, ok := orderSortColumns[strings.TrimPrefix(strings.TrimSpace(key), "-")]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Normalize completa la paginación con valores por defecto y dentro de los límites.
+func (f *OrderFilter) Normalize() {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.Limit < 1 {
+		f.Limit = DefaultPageSize
+	}
+	if f.Limit > MaxPageSize {
+		f.Limit = MaxPageSize
+	}
+}
+
+func (f *OrderFilter) apply(db *gorm.DB) *gorm.DB {
+	if len(f.Statuses) > 0 {
+		statuses := append([]string(nil), f.Statuses...)
+		query := "status IN ?"
+		for _, status := range f.Statuses {
+			if status == models.OrderStatusDraft {
+				// Las órdenes anteriores al flujo de estados ("En Proceso" o sin
+				// estado) se tratan como borradores, igual que en las transiciones.
+				statuses = append(statuses, models.OrderStatusLegacy, "")
+				query = "(status IN ? OR status IS NULL)"
+				break
+			}
+		}
+		db = db.Where(query, statuses)
+	}
+	if f.MemoDateFrom != nil {
+		db = db.Where("memo_date >= ?", *f.MemoDateFrom)
+	}
+	if f.MemoDateTo != nil {
+		db = db.Where("memo_date < ?", f.MemoDateTo.AddDate(0, 0, 1))
+	}
+	if f.BudgetDateFrom != nil {
+		db = db.Where("budget_date >= ?", *f.BudgetDateFrom)
+	}
+	if f.BudgetDateTo != nil {
+		db = db.Where("budget_date < ?", f.BudgetDateTo.AddDate(0, 0, 1))
+	}
+	if f.ProviderID != nil {
+		db = db.Where("provider_id = ?", *f.ProviderID)
+	}
+	if f.Provider != "" {
+		db = db.Where("provider ILIKE ?", likePattern(f.Provider))
+	}
+	if f.UnitID != nil {
+		db = db.Where("unit_id = ?", *f.UnitID)
+	}
+	if f.RequestingUnit != "" {
+		db = db.Where("requesting_unit ILIKE ?", likePattern(f.RequestingUnit))
+	}
+	if f.MinAmount != nil {
+		db = db.Where("total_amount >= ?", *f.MinAmount)
+	}
+	if f.MaxAmount != nil {
+		db = db.Where("total_amount <= ?", *f.MaxAmount)
+	}
+	if f.Search != "" {
+		pattern := likePattern(f.Search)
+		db = db.Where("(concept ILIKE ? OR subject ILIKE ?)", pattern, pattern)
+	}
+	return db
+}
+
+// orderBy traduce Sort a la cláusula ORDER BY; por defecto, las más recientes primero.
+func (f *OrderFilter) orderBy(db *gorm.DB) *gorm.DB {
+	sort := f.Sort
+	if sort == "" {
+		sort = "-createdAt"
+	}
+	for _, key := range strings.Split(sort, ",") {
+		key = strings.TrimSpace(key)
+		direction := "asc"
+		if strings.HasPrefix(key, "-") {
+			direction = "desc"
+			key = key[1:]
+		}
+		if column, ok := orderSortColumns[key]; ok {
+			db = db.Order(column + " " + direction)
+		}
+	}
+	// Desempate estable para que la paginación no repita ni salte filas.
+	return db.Order("id desc")
+}
+
+// likePattern escapa los comodines de LIKE para buscar el texto literal.
+func likePattern(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(strings.TrimSpace(s)) + "%"
+}
+
+func (r *orderRepository) GetAllOrders(ctx context.Context, filter OrderFilter) ([]models.Order, int64, error) {
+	filter.Normalize()
+
+	var total int64
+	if err := filter.apply(r.db.WithContext(ctx).Model(&models.Order{})).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []models.Order
+	query := filter.orderBy(filter.apply(r.withReferences(r.db.WithContext(ctx)))).
+		Limit(filter.Limit).
+		Offset((filter.Page - 1) * filter.Limit)
+	if err := query.Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/toor/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type OrderItemRepository interface {
+	Create(ctx context.Context, item *models.OrderItem) error
+	GetByOrder(ctx context.Context, orderID uint) ([]models.OrderItem, error)
+	GetByID(ctx context.Context, orderID, itemID uint) (*models.OrderItem, error)
+	Update(ctx context.Context, item *models.OrderItem) error
+	Delete(ctx context.Context, orderID, itemID uint) error
+}
+
+type orderItemRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderItemRepository(db *gorm.DB) OrderItemRepository {
+	return &orderItemRepository{db: db}
+}
+
+func (r *orderItemRepository) Create(ctx context.Context, item *models.OrderItem) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+func (r *orderItemRepository) GetByOrder(ctx context.Context, orderID uint) ([]models.OrderItem, error) {
+	var items []models.OrderItem
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id asc").Find(&items).Error
+	return items, err
+}
+
+// GetByID busca el ítem dentro de la orden indicada, para que no se pueda
+// modificar un ítem de otra orden cambiando solo el ID de la URL.
+func (r *orderItemRepository) GetByID(ctx context.Context, orderID, itemID uint) (*models.OrderItem, error) {
+	var item models.OrderItem
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&item, itemID).Error; err != nil {
+		return nil, notFound(err, ErrOrderItemNotFound)
+	}
+	return &item, nil
+}
+
+func (r *orderItemRepository) Update(ctx context.Context, item *models.OrderItem) error {
+	return r.db.WithContext(ctx).Save(item).Error
+}
+
+func (r *orderItemRepository) Delete(ctx context.Context, orderID, itemID uint) error {
+	result := r.db.WithContext(ctx).Where("order_id = ?", orderID).Delete(&models.OrderItem{}, itemID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderItemNotFound.Wrap(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
@@ -1,14 +1,26 @@
 package repository
 
 import (
+	"context"
+
+	"github.com/toor/backend/internal/apperror"
 	"github.com/toor/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrStatusChanged indica que otra petición cambió el estado de la orden
+// mientras se procesaba la transición.
+var ErrStatusChanged = apperror.Conflict("STATUS_CHANGED", "el estado de la orden cambió durante la operación")
+
 type OrderRepository interface {
-	CreateOrder(order *models.Order) (*models.Order, error)
-	GetAllOrders() ([]models.Order, error)
-	GetOrderById(id uint) (*models.Order, error)
+	CreateOrder(ctx context.Context, order *models.Order) (*models.Order, error)
+	GetAllOrders(ctx context.Context, filter OrderFilter) ([]models.Order, int64, error)
+	GetOrderById(ctx context.Context, id uint) (*models.Order, error)
+	UpdateOrder(ctx context.Context, order *models.Order) error
+	UpdateTotals(ctx context.Context, order *models.Order) error
+	UpdateStatus(ctx context.Context, order *models.Order, entry *models.OrderStatusHistory) error
+	GetStatusHistory(ctx context.Context, orderID uint) ([]models.OrderStatusHistory, error)
 }
 
 type orderRepository struct {
@@ -19,27 +31,105 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
 	return &orderRepository{db: db}
 }
 
-func (r *orderRepository) CreateOrder(order *models.Order) (*models.Order, error) {
-	if err := r.db.Create(order).Error; err != nil {
+// CreateOrder inserta la orden junto con sus ítems (GORM crea la asociación).
+// Los datos maestros referenciados nunca se crean ni modifican desde aquí.
+func (r *orderRepository) CreateOrder(ctx context.Context, order *models.Order) (*models.Order, error) {
+	if err := r.db.WithContext(ctx).Omit("Unit", "Official", "ProviderRef").Create(order).Error; err != nil {
 		return nil, err
 	}
 	return order, nil
 }
 
-func (r *orderRepository) GetAllOrders() ([]models.Order, error) {
-	var orders []models.Order
-	if err := r.db.Order("created_at desc").Find(&orders).Error; err != nil {
-		return nil, err
-	}
-	return orders, nil
-}
-
-func (r *orderRepository) GetOrderById(id uint) (*models.Order, error) {
+func (r *orderRepository) GetOrderById(ctx context.Context, id uint) (*models.Order, error) {
 	var order models.Order
 	// db.First buscará por clave primaria. Es crucial devolver el error
 	// para que podamos manejar el 'not found' en la capa superior.
-	if err := r.db.First(&order, id).Error; err != nil {
-		return nil, err
+	err := r.withReferences(r.db.WithContext(ctx)).Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id asc")
+	}).First(&order, id).Error
+	if err != nil {
+		return nil, notFound(err, ErrOrderNotFound)
 	}
 	return &order, nil
-}
\ No newline at end of file
+}
+
+// withReferences precarga la unidad, el funcionario (con su cargo) y el proveedor.
+// Si alguno fue eliminado, la relación queda vacía y se usan los nombres guardados en la orden.
+func (r *orderRepository) withReferences(db *gorm.DB) *gorm.DB {
+	return db.Preload("Unit").Preload("Official.Position").Preload("ProviderRef")
+}
+
+// UpdateOrder guarda los campos editables de la orden. El estado, el número de
+// memo y las asociaciones no se tocan aquí; si otra petición cambió el estado
+// mientras tanto, se devuelve ErrStatusChanged.
+func (r *orderRepository) UpdateOrder(ctx context.Context, order *models.Order) error {
+	result := r.db.WithContext(ctx).Model(order).
+		Where("status = ?", order.Status).
+		Select("*").
+		Omit(clause.Associations, "Status", "MemoNumber", "AccountPointNumber", "PurchaseOrderNumber", "CreatedAt").
+		Updates(order)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStatusChanged
+	}
+	return nil
+}
+
+// UpdateTotals persiste únicamente los montos calculados de la orden y de sus
+// ítems, junto con la conversión a bolívares y la modalidad determinada.
+func (r *orderRepository) UpdateTotals(ctx context.Context, order *models.Order) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(order).
+			Select("base_amount", "iva_amount", "total_amount", "applied_tax_rate",
+				"currency", "exchange_rate_id", "exchange_rate", "exchange_rate_date",
+				"base_amount_ves", "iva_amount_ves", "total_amount_ves",
+				"contract_type", "price_inquiry_type", "tax_unit_value", "amount_ut",
+				"required_modality", "modality_mismatch").
+			Updates(order).Error
+		if err != nil {
+			return err
+		}
+		for i := range order.Items {
+			item := &order.Items[i]
+			err := tx.Model(item).
+				Select("tax_code", "tax_rate", "line_total").
+				Updates(item).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateStatus cambia el estado de la orden, guarda los números de documento
+// asignados en el paso y registra el movimiento en el historial dentro de la
+// misma transacción. La actualización solo se aplica si
+// la orden sigue en entry.FromStatus, para no pisar una transición concurrente.
+func (r *orderRepository) UpdateStatus(ctx context.Context, order *models.Order, entry *models.OrderStatusHistory) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Order{}).
+			Where("id = ? AND status = ?", order.ID, entry.FromStatus).
+			Updates(map[string]interface{}{
+				"status":                entry.ToStatus,
+				"account_point_number":  order.AccountPointNumber,
+				"purchase_order_number": order.PurchaseOrderNumber,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrStatusChanged
+		}
+		order.Status = entry.ToStatus
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *orderRepository) GetStatusHistory(ctx context.Context, orderID uint) ([]models.OrderStatusHistory, error) {
+	var history []models.OrderStatusHistory
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("created_at asc, id asc").Find(&history).Error
+	return history, err
+}
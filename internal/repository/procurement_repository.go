@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/toor/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type ProcurementRepository interface {
+	// Unidad tributaria
+	CreateTaxUnit(ctx context.Context, value *models.TaxUnitValue) error
+	GetAllTaxUnits(ctx context.Context) ([]models.TaxUnitValue, error)
+	GetTaxUnitByID(ctx context.Context, id uint) (*models.TaxUnitValue, error)
+	UpdateTaxUnit(ctx context.Context, value *models.TaxUnitValue) error
+	DeleteTaxUnit(ctx context.Context, id uint) error
+	// FindEffectiveTaxUnit devuelve el valor de la UT vigente en la fecha dada.
+	FindEffectiveTaxUnit(ctx context.Context, date time.Time) (*models.TaxUnitValue, error)
+
+	// Umbrales por modalidad
+	CreateThreshold(ctx context.Context, threshold *models.ProcurementThreshold) error
+	GetAllThresholds(ctx context.Context) ([]models.ProcurementThreshold, error)
+	GetThresholdByID(ctx context.Context, id uint) (*models.ProcurementThreshold, error)
+	UpdateThreshold(ctx context.Context, threshold *models.ProcurementThreshold) error
+	DeleteThreshold(ctx context.Context, id uint) error
+	// GetThresholdsByContractType devuelve los umbrales del tipo ordenados de
+	// menor a mayor límite, con el umbral sin límite al final.
+	GetThresholdsByContractType(ctx context.Context, contractType string) ([]models.ProcurementThreshold, error)
+	CountThresholds(ctx context.Context) (int64, error)
+}
+
+type procurementRepository struct {
+	db *gorm.DB
+}
+
+func NewProcurementRepository(db *gorm.DB) ProcurementRepository {
+	return &procurementRepository{db: db}
+}
+
+// Unidad tributaria
+func (r *procurementRepository) CreateTaxUnit(ctx context.Context, value *models.TaxUnitValue) error {
+	err := r.db.WithContext(ctx).Create(value).Error
+	return duplicate(ctx, r.db, err, value)
+}
+func (r *procurementRepository) GetAllTaxUnits(ctx context.Context) ([]models.TaxUnitValue, error) {
+	var values []models.TaxUnitValue
+	err := r.db.WithContext(ctx).Order("valid_from desc").Find(&values).Error
+	return values, err
+}
+func (r *procurementRepository) GetTaxUnitByID(ctx context.Context, id uint) (*models.TaxUnitValue, error) {
+	var value models.TaxUnitValue
+	if err := r.db.WithContext(ctx).First(&value, id).Error; err != nil {
+		return nil, notFound(err, ErrTaxUnitNotFound)
+	}
+	return &value, nil
+}
+func (r *procurementRepository) UpdateTaxUnit(ctx context.Context, value *models.TaxUnitValue) error {
+	err := r.db.WithContext(ctx).Save(value).Error
+	return duplicate(ctx, r.db, err, value)
+}
+func (r *procurementRepository) DeleteTaxUnit(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.TaxUnitValue{}, id).Error
+}
+func (r *procurementRepository) FindEffectiveTaxUnit(ctx context.Context, date time.Time) (*models.TaxUnitValue, error) {
+	var value models.TaxUnitValue
+	err := r.db.WithContext(ctx).Where("valid_from <= ?", date).Order("valid_from desc").First(&value).Error
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// Umbrales por modalidad
+func (r *procurementRepository) CreateThreshold(ctx context.Context, threshold *models.ProcurementThreshold) error {
+	return r.db.WithContext(ctx).Create(threshold).Error
+}
+func (r *procurementRepository) GetAllThresholds(ctx context.Context) ([]models.ProcurementThreshold, error) {
+	var thresholds []models.ProcurementThreshold
+	err := r.db.WithContext(ctx).Order("contract_type asc, max_ut asc nulls last").Find(&thresholds).Error
+	return thresholds, err
+}
+func (r *procurementRepository) GetThresholdByID(ctx context.Context, id uint) (*models.ProcurementThreshold, error) {
+	var threshold models.ProcurementThreshold
+	if err := r.db.WithContext(ctx).First(&threshold, id).Error; err != nil {
+		return nil, notFound(err, ErrThresholdNotFound)
+	}
+	return &threshold, nil
+}
+func (r *procurementRepository) UpdateThreshold(ctx context.Context, threshold *models.ProcurementThreshold) error {
+	return r.db.WithContext(ctx).Save(threshold).Error
+}
+func (r *procurementRepository) DeleteThreshold(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.ProcurementThreshold{}, id).Error
+}
+func (r *procurementRepository) GetThresholdsByContractType(ctx context.Context, contractType string) ([]models.ProcurementThreshold, error) {
+	var thresholds []models.ProcurementThreshold
+	err := r.db.WithContext(ctx).Where("contract_type = ?", contractType).Order("max_ut asc nulls last").Find(&thresholds).Error
+	return thresholds, err
+}
+func (r *procurementRepository) CountThresholds(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.ProcurementThreshold{}).Count(&count).Error
+	return count, err
+}
@@ -1,16 +1,19 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/toor/backend/internal/models"
 	"gorm.io/gorm"
 )
 
 type ProviderRepository interface {
-	Create(provider *models.Provider) error
-	GetAll() ([]models.Provider, error)
-	GetByID(id uint) (*models.Provider, error)
-	Update(provider *models.Provider) error
-	Delete(id uint) error
+	Create(ctx context.Context, provider *models.Provider) error
+	GetAll(ctx context.Context) ([]models.Provider, error)
+	GetByID(ctx context.Context, id uint) (*models.Provider, error)
+	Update(ctx context.Context, provider *models.Provider) error
+	Delete(ctx context.Context, id uint) error
+	IsInUse(ctx context.Context, id uint) (bool, error)
 }
 
 type providerRepository struct {
@@ -21,26 +24,40 @@ func NewProviderRepository(db *gorm.DB) ProviderRepository {
 	return &providerRepository{db: db}
 }
 
-func (r *providerRepository) Create(provider *models.Provider) error {
-	return r.db.Create(provider).Error
+func (r *providerRepository) Create(ctx context.Context, provider *models.Provider) error {
+	err := r.db.WithContext(ctx).Create(provider).Error
+	return duplicate(ctx, r.db, err, provider)
 }
 
-func (r *providerRepository) GetAll() ([]models.Provider, error) {
+func (r *providerRepository) GetAll(ctx context.Context) ([]models.Provider, error) {
 	var providers []models.Provider
-	err := r.db.Order("name asc").Find(&providers).Error
+	err := r.db.WithContext(ctx).Order("name asc").Find(&providers).Error
 	return providers, err
 }
 
-func (r *providerRepository) GetByID(id uint) (*models.Provider, error) {
+func (r *providerRepository) GetByID(ctx context.Context, id uint) (*models.Provider, error) {
 	var provider models.Provider
-	err := r.db.First(&provider, id).Error
-	return &provider, err
+	if err := r.db.WithContext(ctx).First(&provider, id).Error; err != nil {
+		return nil, notFound(err, ErrProviderNotFound)
+	}
+	return &provider, nil
+}
+
+func (r *providerRepository) Update(ctx context.Context, provider *models.Provider) error {
+	err := r.db.WithContext(ctx).Save(provider).Error
+	return duplicate(ctx, r.db, err, provider)
 }
 
-func (r *providerRepository) Update(provider *models.Provider) error {
-	return r.db.Save(provider).Error
+func (r *providerRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Provider{}, id).Error
 }
 
-func (r *providerRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Provider{}, id).Error
-}
\ No newline at end of file
+// IsInUse indica si alguna orden hace referencia al proveedor.
+func (r *providerRepository) IsInUse(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Order{}).Where("provider_id = ?", id).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/toor/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type QuotationRepository interface {
+	Create(ctx context.Context, quotation *models.Quotation) error
+	GetByOrder(ctx context.Context, orderID uint) ([]models.Quotation, error)
+	GetByID(ctx context.Context, orderID, quotationID uint) (*models.Quotation, error)
+	Update(ctx context.Context, quotation *models.Quotation) error
+	Delete(ctx context.Context, orderID, quotationID uint) error
+	// MarkSelected deja como seleccionada únicamente la cotización indicada.
+	MarkSelected(ctx context.Context, orderID, quotationID uint) error
+}
+
+type quotationRepository struct {
+	db *gorm.DB
+}
+
+func NewQuotationRepository(db *gorm.DB) QuotationRepository {
+	return &quotationRepository{db: db}
+}
+
+func (r *quotationRepository) Create(ctx context.Context, quotation *models.Quotation) error {
+	err := r.db.WithContext(ctx).Omit("Provider").Create(quotation).Error
+	return duplicate(ctx, r.db, err, quotation)
+}
+
+func (r *quotationRepository) GetByOrder(ctx context.Context, orderID uint) ([]models.Quotation, error) {
+	var quotations []models.Quotation
+	err := r.db.WithContext(ctx).Preload("Provider").Where("order_id = ?", orderID).Order("id asc").Find(&quotations).Error
+	return quotations, err
+}
+
+// GetByID busca la cotización dentro de la orden indicada.
+func (r *quotationRepository) GetByID(ctx context.Context, orderID, quotationID uint) (*models.Quotation, error) {
+	var quotation models.Quotation
+	err := r.db.WithContext(ctx).Preload("Provider").Where("order_id = ?", orderID).First(&quotation, quotationID).Error
+	if err != nil {
+		return nil, notFound(err, ErrQuotationNotFound)
+	}
+	return &quotation, nil
+}
+
+func (r *quotationRepository) Update(ctx context.Context, quotation *models.Quotation) error {
+	err := r.db.WithContext(ctx).Omit("Provider").Save(quotation).Error
+	return duplicate(ctx, r.db, err, quotation)
+}
+
+func (r *quotationRepository) Delete(ctx context.Context, orderID, quotationID uint) error {
+	result := r.db.WithContext(ctx).Where("order_id = ?", orderID).Delete(&models.Quotation{}, quotationID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrQuotationNotFound.Wrap(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (r *quotationRepository) MarkSelected(ctx context.Context, orderID, quotationID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Quotation{}).
+			Where("order_id = ? AND id <> ?", orderID, quotationID).
+			Update("selected", false).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Model(&models.Quotation{}).
+			Where("order_id = ? AND id = ?", orderID, quotationID).
+			Update("selected", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrQuotationNotFound.Wrap(gorm.ErrRecordNotFound)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/toor/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleRepository interface {
+	// Permisos
+	// EnsurePermissions registra los permisos que falten (por código).
+	EnsurePermissions(ctx context.Context, permissions []models.Permission) error
+	GetAllPermissions(ctx context.Context) ([]models.Permission, error)
+	GetPermissionsByCodes(ctx context.Context, codes []string) ([]models.Permission, error)
+
+	// Roles
+	CreateRole(ctx context.Context, role *models.Role) error
+	GetAllRoles(ctx context.Context) ([]models.Role, error)
+	GetRoleByID(ctx context.Context, id uint) (*models.Role, error)
+	GetRolesByIDs(ctx context.Context, ids []uint) ([]models.Role, error)
+	GetRoleByCode(ctx context.Context, code string) (*models.Role, error)
+	// UpdateRole guarda el rol y reemplaza su lista de permisos.
+	UpdateRole(ctx context.Context, role *models.Role) error
+	DeleteRole(ctx context.Context, id uint) error
+	CountRoles(ctx context.Context) (int64, error)
+	IsRoleInUse(ctx context.Context, id uint) (bool, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// Permisos
+func (r *roleRepository) EnsurePermissions(ctx context.Context, permissions []models.Permission) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&permissions).Error
+}
+func (r *roleRepository) GetAllPermissions(ctx context.Context) ([]models.Permission, error) {
+	var permissions []models.Permission
+	err := r.db.WithContext(ctx).Order("code asc").Find(&permissions).Error
+	return permissions, err
+}
+func (r *roleRepository) GetPermissionsByCodes(ctx context.Context, codes []string) ([]models.Permission, error) {
+	var permissions []models.Permission
+	err := r.db.WithContext(ctx).Where("code IN ?", codes).Order("code asc").Find(&permissions).Error
+	return permissions, err
+}
+
+// Roles
+func (r *roleRepository) CreateRole(ctx context.Context, role *models.Role) error {
+	err := r.db.WithContext(ctx).Create(role).Error
+	return duplicate(ctx, r.db, err, role)
+}
+func (r *roleRepository) GetAllRoles(ctx context.Context) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.WithContext(ctx).Preload("Permissions").Order("code asc").Find(&roles).Error
+	return roles, err
+}
+func (r *roleRepository) GetRoleByID(ctx context.Context, id uint) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").First(&role, id).Error; err != nil {
+		return nil, notFound(err, ErrRoleNotFound)
+	}
+	return &role, nil
+}
+func (r *roleRepository) GetRolesByIDs(ctx context.Context, ids []uint) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&roles).Error
+	return roles, err
+}
+func (r *roleRepository) GetRoleByCode(ctx context.Context, code string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+func (r *roleRepository) UpdateRole(ctx context.Context, role *models.Role) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Association("Permissions").Replace(role.Permissions)
+	})
+	return duplicate(ctx, r.db, err, role)
+}
+func (r *roleRepository) DeleteRole(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Role{}, id).Error
+}
+func (r *roleRepository) CountRoles(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Role{}).Count(&count).Error
+	return count, err
+}
+func (r *roleRepository) IsRoleInUse(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("user_roles").Where("role_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
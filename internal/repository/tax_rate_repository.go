@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/toor/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type TaxRateRepository interface {
+	Create(ctx context.Context, rate *models.TaxRate) error
+	GetAll(ctx context.Context) ([]models.TaxRate, error)
+	GetByID(ctx context.Context, id uint) (*models.TaxRate, error)
+	Update(ctx context.Context, rate *models.TaxRate) error
+	Delete(ctx context.Context, id uint) error
+	// FindEffective devuelve la alícuota del código indicado vigente en la fecha dada.
+	FindEffective(ctx context.Context, code string, date time.Time) (*models.TaxRate, error)
+	// HasOverlap indica si otro rango del mismo código se solapa con [from, to].
+	HasOverlap(ctx context.Context, code string, from time.Time, to *time.Time, excludeID uint) (bool, error)
+	Count(ctx context.Context) (int64, error)
+}
+
+type taxRateRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxRateRepository(db *gorm.DB) TaxRateRepository {
+	return &taxRateRepository{db: db}
+}
+
+func (r *taxRateRepository) Create(ctx context.Context, rate *models.TaxRate) error {
+	return r.db.WithContext(ctx).Create(rate).Error
+}
+
+func (r *taxRateRepository) GetAll(ctx context.Context) ([]models.TaxRate, error) {
+	var rates []models.TaxRate
+	err := r.db.WithContext(ctx).Order("code asc, valid_from desc").Find(&rates).Error
+	return rates, err
+}
+
+func (r *taxRateRepository) GetByID(ctx context.Context, id uint) (*models.TaxRate, error) {
+	var rate models.TaxRate
+	if err := r.db.WithContext(ctx).First(&rate, id).Error; err != nil {
+		return nil, notFound(err, ErrTaxRateNotFound)
+	}
+	return &rate, nil
+}
+
+func (r *taxRateRepository) Update(ctx context.Context, rate *models.TaxRate) error {
+	return r.db.WithContext(ctx).Save(rate).Error
+}
+
+func (r *taxRateRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.TaxRate{}, id).Error
+}
+
+func (r *taxRateRepository) FindEffective(ctx context.Context, code string, date time.Time) (*models.TaxRate, error) {
+	var rate models.TaxRate
+	err := r.db.WithContext(ctx).
+		Where("code = ? AND valid_from <= ? AND (valid_to IS NULL OR valid_to >= ?)", code, date, date).
+		Order("valid_from desc").
+		First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *taxRateRepository) HasOverlap(ctx context.Context, code string, from time.Time, to *time.Time, excludeID uint) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&models.TaxRate{}).
+		Where("code = ? AND id <> ?", code, excludeID).
+		Where("valid_to IS NULL OR valid_to >= ?", from)
+	if to != nil {
+		query = query.Where("valid_from <= ?", *to)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *taxRateRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.TaxRate{}).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/toor/backend/internal/apperror"
+	"gorm.io/gorm"
+)
+
+// uniqueViolationCode es el código de PostgreSQL para una violación de un
+// índice o restricción única.
+const uniqueViolationCode = "23505"
+
+// ErrDuplicate indica que el registro repite el valor de un campo único de
+// otro registro. Sus detalles (DuplicateDetails) indican el campo y el ID del
+// registro existente, para que el cliente pueda enlazarlo.
+var ErrDuplicate = apperror.Conflict("DUPLICATE", "ya existe un registro con esos datos")
+
+// DuplicateDetails son los detalles de ErrDuplicate. ExistingID es 0 si no se
+// pudo determinar el registro existente.
+type DuplicateDetails struct {
+	Field      string `json:"field"`
+	Value      any    `json:"value"`
+	ExistingID uint   `json:"existingId,omitempty"`
+}
+
+// uniqueIndex describe un índice único de la base de datos.
+type uniqueIndex struct {
+	// field es el nombre en el JSON del campo que se informa al cliente.
+	field string
+	// columns son las columnas del índice; la primera corresponde a field.
+	columns []string
+	// message describe el conflicto y recibe el valor de field.
+	message string
+}
+
+// uniqueIndexes son los índices únicos que pueden violarse al crear o
+// modificar un registro, por nombre del índice.
+var uniqueIndexes = map[string]uniqueIndex{
+	"idx_providers_rif_active":                {"rif", []string{"rif"}, "ya existe un proveedor con el RIF %v"},
+	"idx_units_name_active":                   {"name", []string{"name"}, "ya existe una unidad con el nombre %q"},
+	"idx_units_code_active":                   {"code", []string{"code"}, "ya existe una unidad con el código %q"},
+	"idx_positions_name_active":               {"name", []string{"name"}, "ya existe un cargo con el nombre %q"},
+	"idx_users_username_active":               {"username", []string{"username"}, "ya existe un usuario con el nombre %q"},
+	"idx_roles_code_active":                   {"code", []string{"code"}, "ya existe un rol con el código %q"},
+	"idx_document_types_code_active":          {"code", []string{"code"}, "ya existe un tipo de documento con el código %q"},
+	"idx_tax_unit_values_valid_from_active":   {"validFrom", []string{"valid_from"}, "ya existe un valor de la unidad tributaria vigente desde %v"},
+	"idx_exchange_rates_date_currency_active": {"date", []string{"date", "currency"}, "ya existe una tasa de cambio de esa moneda para el %v"},
+	"idx_quotations_order_provider_active":    {"providerId", []string{"provider_id", "order_id"}, "el proveedor %v ya tiene una cotización registrada para esta orden"},
+}
+
+// duplicate traduce una violación de un índice único en ErrDuplicate con el
+// campo repetido y el ID del registro que ya lo tiene. model es el registro
+// que se intentaba guardar. Cualquier otro error (o nil) se devuelve sin
+// cambios.
+func duplicate(ctx context.Context, db *gorm.DB, err error, model any) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolationCode {
+		return err
+	}
+	index, ok := uniqueIndexes[pgErr.ConstraintName]
+	if !ok {
+		return ErrDuplicate.Wrap(err)
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return ErrDuplicate.Wrap(err)
+	}
+	record := reflect.Indirect(reflect.ValueOf(model))
+	// Dentro de una transacción la búsqueda falla, porque PostgreSQL la aborta
+	// tras la violación; en ese caso se informa el campo sin el ID.
+	query := db.WithContext(ctx).Table(stmt.Schema.Table)
+	var values []any
+	for _, column := range index.columns {
+		field := stmt.Schema.LookUpField(column)
+		if field == nil {
+			return ErrDuplicate.Wrap(err)
+		}
+		value, _ := field.ValueOf(ctx, record)
+		values = append(values, value)
+		query = query.Where(fmt.Sprintf("%q = ?", column), value)
+	}
+	if stmt.Schema.LookUpField("deleted_at") != nil {
+		query = query.Where("deleted_at IS NULL")
+	}
+
+	details := DuplicateDetails{Field: index.field, Value: values[0]}
+	var ids []uint
+	if query.Limit(1).Pluck("id", &ids).Error == nil && len(ids) > 0 {
+		details.ExistingID = ids[0]
+	}
+	shown := values[0]
+	if date, ok := shown.(time.Time); ok {
+		shown = date.Format("02/01/2006")
+	}
+	return ErrDuplicate.
+		WithMessage(fmt.Sprintf(index.message, shown)).
+		WithDetails(details).
+		Wrap(err)
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// UnitOfWork agrupa operaciones de varios repositorios en una sola
+// transacción: si fn devuelve un error se revierte todo. Los repositorios
+// participan porque sus constructores aceptan tanto la conexión como una
+// transacción (*gorm.DB en ambos casos).
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(tx Repositories) error) error
+}
+
+// Repositories da acceso a repositorios que trabajan sobre la transacción en
+// curso. Solo son válidos dentro de la función pasada a Do.
+type Repositories interface {
+	Orders() OrderRepository
+	Items() OrderItemRepository
+	Quotations() QuotationRepository
+	Counters() CounterRepository
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(tx Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&txRepositories{tx: tx})
+	})
+}
+
+// txRepositories construye los repositorios con la transacción en lugar de
+// la conexión; las transacciones que abran internamente se anidan con savepoints.
+type txRepositories struct {
+	tx *gorm.DB
+}
+
+func (r *txRepositories) Orders() OrderRepository         { return NewOrderRepository(r.tx) }
+func (r *txRepositories) Items() OrderItemRepository      { return NewOrderItemRepository(r.tx) }
+func (r *txRepositories) Quotations() QuotationRepository { return NewQuotationRepository(r.tx) }
+func (r *txRepositories) Counters() CounterRepository     { return NewCounterRepository(r.tx) }
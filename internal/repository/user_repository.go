@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/toor/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	GetAll(ctx context.Context) ([]models.User, error)
+	// Update guarda el usuario y reemplaza su lista de roles.
+	Update(ctx context.Context, user *models.User) error
+	Count(ctx context.Context) (int64, error)
+	// GetWithoutRoles devuelve los usuarios que no tienen ningún rol asignado.
+	GetWithoutRoles(ctx context.Context) ([]models.User, error)
+
+	// Lista de tokens revocados
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) (bool, error)
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// DeleteExpiredTokens borra de la lista los tokens que ya vencieron.
+	DeleteExpiredTokens(ctx context.Context, now time.Time) error
+}
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// Create inserta el usuario y lo vincula a sus roles (que ya deben existir).
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	err := r.db.WithContext(ctx).Omit("Official", "Roles.*").Create(user).Error
+	return duplicate(ctx, r.db, err, user)
+}
+
+// GetByID precarga el funcionario y los roles con sus permisos.
+func (r *userRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Preload("Official.Position").Preload("Roles.Permissions").First(&user, id).Error
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+	return &user, nil
+}
+
+func (r *userRepository) GetAll(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).Preload("Official").Preload("Roles").Order("username asc").Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Official", "Roles").Save(user).Error; err != nil {
+			return err
+		}
+		return tx.Model(user).Omit("Roles.*").Association("Roles").Replace(user.Roles)
+	})
+	return duplicate(ctx, r.db, err, user)
+}
+
+func (r *userRepository) GetWithoutRoles(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).Where("NOT EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id)").Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Count(&count).Error
+	return count, err
+}
+
+// RevokeToken es idempotente: revocar dos veces el mismo token no falla.
+// Devuelve false si el token ya estaba revocado.
+func (r *userRepository) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.RevokedToken{JTI: jti, ExpiresAt: expiresAt})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *userRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *userRepository) DeleteExpiredTokens(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.RevokedToken{}).Error
+}
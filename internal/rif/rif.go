@@ -0,0 +1,103 @@
+// Package rif valida y normaliza el Registro de Información Fiscal (RIF)
+// venezolano.
+//
+// La forma canónica es X-NNNNNNNN-D: la letra del tipo de contribuyente, el
+// número de ocho dígitos (completado con ceros a la izquierda) y el dígito
+// verificador. Se aceptan variantes con minúsculas, sin guiones o con
+// puntos y espacios (ej. "j123456789", "J-12.345.678-9").
+//
+// El dígito verificador se calcula con el módulo 11 del SENIAT: el valor de
+// la letra se multiplica por 4 y cada dígito del número por 3, 2, 7, 6, 5,
+// 4, 3 y 2; el dígito es 11 menos el resto de dividir la suma entre 11, o 0
+// si el resultado es 10 u 11.
+package rif
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// numberDigits es la cantidad de dígitos del número, sin el verificador.
+const numberDigits = 8
+
+var (
+	ErrEmpty         = errors.New("el RIF está vacío")
+	ErrInvalidFormat = errors.New("el RIF debe tener la forma X-NNNNNNNN-D")
+	ErrInvalidPrefix = errors.New("el RIF debe comenzar con V, E, J, P, G o C")
+	ErrCheckDigit    = errors.New("el dígito verificador del RIF no es correcto")
+)
+
+// checkDigitWeights son los factores de cada dígito del número.
+var checkDigitWeights = [numberDigits]int{3, 2, 7, 6, 5, 4, 3, 2}
+
+// prefixValues es el valor de cada tipo de contribuyente en el cálculo del
+// dígito verificador: V natural, E extranjero, J jurídico, P pasaporte,
+// G gobierno y C consejo comunal.
+var prefixValues = map[byte]int{'V': 1, 'E': 2, 'J': 3, 'C': 3, 'P': 4, 'G': 5}
+
+// RIF es un RIF válido.
+type RIF struct {
+	Prefix     byte
+	Number     string // ocho dígitos
+	CheckDigit byte
+}
+
+// String devuelve la forma canónica X-NNNNNNNN-D.
+func (r RIF) String() string {
+	return fmt.Sprintf("%c-%s-%c", r.Prefix, r.Number, r.CheckDigit)
+}
+
+// Parse interpreta s, que debe terminar en el dígito verificador. Un número
+// de menos de ocho dígitos se completa con ceros a la izquierda.
+func Parse(s string) (RIF, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '.', ' ', '\t':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(s))
+	if cleaned == "" {
+		return RIF{}, ErrEmpty
+	}
+
+	prefix, digits := cleaned[0], cleaned[1:]
+	if _, ok := prefixValues[prefix]; !ok {
+		return RIF{}, ErrInvalidPrefix
+	}
+	if len(digits) < 2 || len(digits) > numberDigits+1 || strings.Trim(digits, "0123456789") != "" {
+		return RIF{}, ErrInvalidFormat
+	}
+
+	number := strings.Repeat("0", numberDigits+1-len(digits)) + digits[:len(digits)-1]
+	r := RIF{Prefix: prefix, Number: number, CheckDigit: digits[len(digits)-1]}
+	if expected := CheckDigit(prefix, number); r.CheckDigit != expected {
+		return RIF{}, fmt.Errorf("%w (para %c-%s debería ser %c)", ErrCheckDigit, prefix, number, expected)
+	}
+	return r, nil
+}
+
+// Normalize devuelve s en la forma canónica X-NNNNNNNN-D, o un error si no es
+// un RIF válido.
+func Normalize(s string) (string, error) {
+	r, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return r.String(), nil
+}
+
+// CheckDigit calcula el dígito verificador de la letra prefix y el número
+// de ocho dígitos number.
+func CheckDigit(prefix byte, number string) byte {
+	sum := prefixValues[prefix] * 4
+	for i, weight := range checkDigitWeights {
+		sum += int(number[i]-'0') * weight
+	}
+	digit := 11 - sum%11
+	if digit >= 10 {
+		digit = 0
+	}
+	return byte('0' + digit)
+}
@@ -0,0 +1,79 @@
+package rif
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		err   error
+	}{
+		{name: "natural", input: "V-12345678-1", want: "V-12345678-1"},
+		{name: "extranjero sin guiones y en minúsculas", input: "e123456788", want: "E-12345678-8"},
+		{name: "jurídico con puntos", input: "J-12.345.678-4", want: "J-12345678-4"},
+		{name: "pasaporte", input: "P-12345678-0", want: "P-12345678-0"},
+		{name: "gobierno", input: "G 12345678 7", want: "G-12345678-7"},
+		{name: "consejo comunal", input: "C-12345678-4", want: "C-12345678-4"},
+		{name: "resultado 10 pasa a 0", input: "J-00002961-0", want: "J-00002961-0"},
+		{name: "resultado 11 pasa a 0", input: "V-01234567-0", want: "V-01234567-0"},
+		{name: "número corto se completa con ceros", input: "V-1234567-0", want: "V-01234567-0"},
+
+		{name: "vacío", input: "", err: ErrEmpty},
+		{name: "solo separadores", input: " - . ", err: ErrEmpty},
+		{name: "prefijo desconocido", input: "X-12345678-1", err: ErrInvalidPrefix},
+		{name: "empieza con dígito", input: "123456781", err: ErrInvalidPrefix},
+		{name: "letra en el número", input: "J-1234A678-4", err: ErrInvalidFormat},
+		{name: "sin número", input: "J-4", err: ErrInvalidFormat},
+		{name: "demasiados dígitos", input: "J-123456789-0", err: ErrInvalidFormat},
+		{name: "dígito verificador incorrecto", input: "J-12345678-5", err: ErrCheckDigit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Parse(%q) error = %v, want %v", tt.input, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		prefix byte
+		number string
+		want   byte
+	}{
+		{'V', "12345678", '1'},
+		{'E', "12345678", '8'},
+		{'J', "12345678", '4'},
+		{'P', "12345678", '0'},
+		{'G', "12345678", '7'},
+		{'C', "12345678", '4'},
+		{'G', "20000303", '0'},
+		{'J', "00002961", '0'},
+	}
+	for _, tt := range tests {
+		if got := CheckDigit(tt.prefix, tt.number); got != tt.want {
+			t.Errorf("CheckDigit(%c, %s) = %c, want %c", tt.prefix, tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+	if got, err := Normalize("J-12345678-5"); err == nil || got != "" {
+		t.Errorf(`Normalize("J-12345678-5") = %q, %v; want "", error`, got, err)
+	}
+}
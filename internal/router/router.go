@@ -4,40 +4,160 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/toor/backend/internal/handlers"
+	"github.com/toor/backend/internal/middleware"
+	"github.com/toor/backend/internal/models"
 )
 
+// New arma el router. Solo /api/ping, el login y el refresh son públicos; el
+// resto de las rutas pasa por authMiddleware y cada grupo exige el permiso de
+// lectura (GET) o escritura (resto de métodos) correspondiente. Todas las
+// rutas pasan por timeoutMiddleware, que limita la duración de las consultas,
+// y por middleware.Errors, que responde los errores con el formato común
+// {code, message, details, requestId}.
 func New(
+	authMiddleware gin.HandlerFunc,
+	timeoutMiddleware gin.HandlerFunc,
+	authHandler *handlers.AuthHandler,
 	orderHandler *handlers.OrderHandler,
 	adminHandler *handlers.AdminHandler,
 	providerHandler *handlers.ProviderHandler,
 	masterDataHandler *handlers.MasterDataHandler,
+	documentHandler *handlers.DocumentHandler,
+	taxRateHandler *handlers.TaxRateHandler,
+	exchangeRateHandler *handlers.ExchangeRateHandler,
+	procurementHandler *handlers.ProcurementHandler,
+	userHandler *handlers.UserHandler,
+	auditHandler *handlers.AuditHandler,
+	documentTypeHandler *handlers.DocumentTypeHandler,
 ) *gin.Engine {
 	r := gin.Default()
+	r.Use(middleware.RequestID(), middleware.Errors())
 
 	config := cors.DefaultConfig()
 	config.AllowOrigins = []string{"http://localhost:4321"}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	config.AddAllowHeaders("Authorization", middleware.RequestIDHeader)
+	config.AddExposeHeaders(middleware.RequestIDHeader)
 	r.Use(cors.New(config))
+	r.Use(timeoutMiddleware)
+	r.NoRoute(handlers.NotFound)
 
-	api := r.Group("/api")
+	public := r.Group("/api")
 	{
-		api.GET("/ping", handlers.Ping)
+		public.GET("/ping", handlers.Ping)
+		public.POST("/auth/login", authHandler.Login)
+		// El refresh se autentica con el propio token de refresh.
+		public.POST("/auth/refresh", authHandler.Refresh)
+	}
+
+	api := r.Group("/api", authMiddleware)
+	{
+		api.POST("/auth/logout", authHandler.Logout)
+		api.GET("/auth/me", authHandler.Me)
 
 		// Rutas de Órdenes
-		orders := api.Group("/orders")
+		orders := api.Group("/orders", middleware.RequireAccess(models.PermOrdersRead, models.PermOrdersWrite))
 		{
 			orders.POST("", orderHandler.CreateOrderHandler)
 			orders.GET("", orderHandler.GetOrdersHandler)
+			orders.GET("/:id", orderHandler.GetOrderByIdHandler)
+			orders.PUT("/:id", orderHandler.UpdateOrderHandler)
+			orders.PATCH("/:id", orderHandler.PatchOrderHandler)
+			orders.POST("/:id/cancel", orderHandler.CancelOrderHandler)
+
+			// Ítems de la orden
+			orders.GET("/:id/items", orderHandler.GetOrderItemsHandler)
+			orders.POST("/:id/items", orderHandler.CreateOrderItemHandler)
+			orders.PUT("/:id/items/:itemId", orderHandler.UpdateOrderItemHandler)
+			orders.DELETE("/:id/items/:itemId", orderHandler.DeleteOrderItemHandler)
+
+			// Cotizaciones y cuadro comparativo
+			orders.GET("/:id/quotations", orderHandler.GetOrderQuotationsHandler)
+			orders.POST("/:id/quotations", orderHandler.CreateOrderQuotationHandler)
+			orders.GET("/:id/quotations/comparison", orderHandler.GetQuotationComparisonHandler)
+			orders.PUT("/:id/quotations/:quotationId", orderHandler.UpdateOrderQuotationHandler)
+			orders.DELETE("/:id/quotations/:quotationId", orderHandler.DeleteOrderQuotationHandler)
+			orders.PUT("/:id/quotations/:quotationId/score", orderHandler.ScoreOrderQuotationHandler)
+			orders.POST("/:id/quotations/:quotationId/select", orderHandler.SelectOrderQuotationHandler)
+
+			// Flujo de estados
+			orders.GET("/:id/transitions", orderHandler.GetOrderTransitionsHandler)
+
+			// Documentos PDF (memo.pdf, account-point.pdf, order.pdf)
+			orders.GET("/:id/documents/:document", documentHandler.GetOrderDocument)
+		}
+
+		// Las transiciones las pueden pedir tanto los analistas como los jefes
+		// de unidad que aprueban; el servicio valida el permiso según el estado.
+		workflow := api.Group("/orders", middleware.RequirePermission(models.PermOrdersRead))
+		{
+			workflow.POST("/:id/transitions", orderHandler.TransitionOrderHandler)
 		}
 
 		// Rutas de Administración
-		admin := api.Group("/admin")
+		admin := api.Group("/admin", middleware.RequireAccess(models.PermAdminRead, models.PermAdminWrite))
 		{
+			// Retirada: responde 410 e indica la ruta de cierre del ejercicio
 			admin.POST("/reset-counters", adminHandler.ResetCountersHandler)
+
+			// Ejercicios fiscales
+			admin.GET("/fiscal-years", adminHandler.GetFiscalYears)
+			admin.POST("/fiscal-years/:year/close", adminHandler.CloseFiscalYear)
+
+			// Contadores de documentos
+			admin.GET("/counters", adminHandler.GetCounters)
+			admin.GET("/counters/:type/preview", adminHandler.PreviewCounter)
+			admin.POST("/counters/:type/adjust", adminHandler.AdjustCounter)
+
+			// Registro de números emitidos
+			admin.GET("/numbers/gaps", adminHandler.GetNumberGaps)
+			admin.POST("/numbers/void", adminHandler.VoidNumber)
+
+			// Tipos de documento y su numeración
+			admin.GET("/document-types", documentTypeHandler.GetDocumentTypes)
+			admin.POST("/document-types", documentTypeHandler.CreateDocumentType)
+			admin.PUT("/document-types/:id", documentTypeHandler.UpdateDocumentType)
+			admin.DELETE("/document-types/:id", documentTypeHandler.DeleteDocumentType)
+
+			// Alícuotas de IVA
+			admin.GET("/tax-rates", taxRateHandler.GetTaxRates)
+			admin.POST("/tax-rates", taxRateHandler.CreateTaxRate)
+			admin.PUT("/tax-rates/:id", taxRateHandler.UpdateTaxRate)
+			admin.DELETE("/tax-rates/:id", taxRateHandler.DeleteTaxRate)
+
+			// Tasas de cambio
+			admin.GET("/exchange-rates", exchangeRateHandler.GetExchangeRates)
+			admin.POST("/exchange-rates", exchangeRateHandler.CreateExchangeRate)
+			admin.POST("/exchange-rates/upload", exchangeRateHandler.UploadExchangeRates)
+			admin.PUT("/exchange-rates/:id", exchangeRateHandler.UpdateExchangeRate)
+			admin.DELETE("/exchange-rates/:id", exchangeRateHandler.DeleteExchangeRate)
+
+			// Unidad tributaria y umbrales de modalidades de contratación
+			admin.GET("/tax-units", procurementHandler.GetTaxUnits)
+			admin.POST("/tax-units", procurementHandler.CreateTaxUnit)
+			admin.PUT("/tax-units/:id", procurementHandler.UpdateTaxUnit)
+			admin.DELETE("/tax-units/:id", procurementHandler.DeleteTaxUnit)
+			admin.GET("/procurement-thresholds", procurementHandler.GetThresholds)
+			admin.POST("/procurement-thresholds", procurementHandler.CreateThreshold)
+			admin.PUT("/procurement-thresholds/:id", procurementHandler.UpdateThreshold)
+			admin.DELETE("/procurement-thresholds/:id", procurementHandler.DeleteThreshold)
+
+			// Usuarios, roles y permisos
+			admin.GET("/permissions", userHandler.GetPermissions)
+			admin.GET("/roles", userHandler.GetRoles)
+			admin.POST("/roles", userHandler.CreateRole)
+			admin.PUT("/roles/:id", userHandler.UpdateRole)
+			admin.DELETE("/roles/:id", userHandler.DeleteRole)
+			admin.GET("/users", userHandler.GetUsers)
+			admin.POST("/users", userHandler.CreateUser)
+			admin.PUT("/users/:id", userHandler.UpdateUser)
 		}
 
+		// Bitácora de auditoría
+		api.GET("/audit", middleware.RequirePermission(models.PermAdminRead), auditHandler.GetAuditEntries)
+
 		// Rutas de Proveedores
-		providers := api.Group("/providers")
+		providers := api.Group("/providers", middleware.RequireAccess(models.PermProvidersRead, models.PermProvidersWrite))
 		{
 			providers.POST("", providerHandler.CreateProvider)
 			providers.GET("", providerHandler.GetProviders)
@@ -46,7 +166,7 @@ func New(
 			providers.DELETE("/:id", providerHandler.DeleteProvider)
 		}
 
-		master := api.Group("/master-data")
+		master := api.Group("/master-data", middleware.RequireAccess(models.PermMasterDataRead, models.PermMasterDataWrite))
 		{
 			// Units
 			master.GET("/units", masterDataHandler.GetUnits)
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/toor/backend/internal/audit"
+	"github.com/toor/backend/internal/models"
+)
+
+// Actor es el usuario que ejecuta una operación, con los permisos de todos
+// sus roles. Los servicios lo reciben explícitamente para verificar permisos
+// y registrar quién hizo cada cambio.
+type Actor struct {
+	UserID   uint
+	Username string
+	// UnitID es la unidad del funcionario vinculado al usuario (nil si no tiene).
+	UnitID *uint
+	Roles  []string
+	// IP desde la que se hizo la petición, para la bitácora de auditoría.
+	IP string
+
+	// Token con el que se autenticó, para poder revocarlo al cerrar sesión.
+	TokenID        string
+	TokenExpiresAt time.Time
+
+	permissions map[string]bool
+	system      bool
+}
+
+// NewActor arma el actor a partir del usuario con sus roles y funcionario precargados.
+func NewActor(user *models.User) *Actor {
+	actor := &Actor{
+		UserID:      user.ID,
+		Username:    user.Username,
+		permissions: map[string]bool{},
+	}
+	if user.Official != nil {
+		unitID := user.Official.UnitID
+		actor.UnitID = &unitID
+	}
+	for _, role := range user.Roles {
+		actor.Roles = append(actor.Roles, role.Code)
+		for _, p := range role.Permissions {
+			actor.permissions[p.Code] = true
+		}
+	}
+	return actor
+}
+
+// SystemActor representa procesos internos (arranque, tareas de consola) que
+// no pasan por un usuario y tienen todos los permisos.
+func SystemActor(name string) *Actor {
+	return &Actor{Username: name, system: true}
+}
+
+// Can indica si el actor tiene el permiso.
+func (a *Actor) Can(permission string) bool {
+	if a == nil {
+		return false
+	}
+	return a.system || a.permissions[permission]
+}
+
+// Context agrega al contexto de la petición el actor con el que los
+// repositorios registran en la bitácora de auditoría quién hizo cada cambio.
+func (a *Actor) Context(ctx context.Context) context.Context {
+	if a == nil {
+		return ctx
+	}
+	info := audit.Actor{Username: a.Username, IP: a.IP}
+	if !a.system {
+		userID := a.UserID
+		info.UserID = &userID
+	}
+	return audit.WithActor(ctx, info)
+}
+
+// Permissions devuelve los códigos de permiso del actor.
+func (a *Actor) Permissions() []string {
+	codes := make([]string, 0, len(a.permissions))
+	for code := range a.permissions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// authorize devuelve ErrForbidden si el actor no tiene el permiso.
+func authorize(actor *Actor, permission string) error {
+	if !actor.Can(permission) {
+		return fmt.Errorf("%w: se requiere el permiso %s", ErrForbidden, permission)
+	}
+	return nil
+}
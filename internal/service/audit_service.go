@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/repository"
+)
+
+// AuditService consulta la bitácora de auditoría. Las entradas las escriben
+// los callbacks del paquete audit, no este servicio.
+type AuditService interface {
+	GetEntries(ctx context.Context, filter repository.AuditFilter) ([]models.AuditEntry, int64, error)
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) GetEntries(ctx context.Context, filter repository.AuditFilter) ([]models.AuditEntry, int64, error) {
+	return s.repo.GetAll(ctx, filter)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/auth"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCredentials se devuelve cuando el usuario no existe, está
+// inactivo o la contraseña no coincide (sin distinguir el caso).
+var ErrInvalidCredentials = apperror.Unauthorized("INVALID_CREDENTIALS", "usuario o contraseña incorrectos")
+
+type AuthService interface {
+	Login(ctx context.Context, username, password string) (*auth.TokenPair, error)
+	// Refresh canjea un token de refresh por un par nuevo; el anterior queda revocado.
+	Refresh(ctx context.Context, refreshToken string) (*auth.TokenPair, error)
+	// Logout revoca el token de acceso del actor y, si se indica, el de refresh.
+	Logout(ctx context.Context, actor *Actor, refreshToken string) error
+	// Authenticate valida un token de acceso y devuelve el usuario con sus
+	// permisos vigentes (se leen de la base de datos en cada petición).
+	Authenticate(ctx context.Context, accessToken string) (*Actor, error)
+	GetUser(ctx context.Context, id uint) (*models.User, error)
+}
+
+type authService struct {
+	repo   repository.UserRepository
+	tokens *auth.TokenManager
+}
+
+func NewAuthService(repo repository.UserRepository, tokens *auth.TokenManager) AuthService {
+	return &authService{repo: repo, tokens: tokens}
+}
+
+func (s *authService) Login(ctx context.Context, username, password string) (*auth.TokenPair, error) {
+	user, err := s.repo.GetByUsername(ctx, strings.TrimSpace(username))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return s.tokens.Issue(user.ID, user.Username)
+}
+
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*auth.TokenPair, error) {
+	claims, err := s.tokens.Parse(refreshToken, auth.TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNotRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+	user, err := s.activeUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// Cada refresh se usa una sola vez: si otra petición lo revocó entre la
+	// verificación y este punto, se rechaza.
+	revoked, err := s.repo.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, auth.ErrInvalidToken
+	}
+	return s.tokens.Issue(user.ID, user.Username)
+}
+
+func (s *authService) Logout(ctx context.Context, actor *Actor, refreshToken string) error {
+	if _, err := s.repo.RevokeToken(ctx, actor.TokenID, actor.TokenExpiresAt); err != nil {
+		return err
+	}
+	if refreshToken != "" {
+		claims, err := s.tokens.Parse(refreshToken, auth.TokenTypeRefresh)
+		if err != nil {
+			return err
+		}
+		if claims.UserID != actor.UserID {
+			return auth.ErrInvalidToken
+		}
+		if _, err := s.repo.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return err
+		}
+	}
+	// Limpieza oportunista de la lista de revocados.
+	if err := s.repo.DeleteExpiredTokens(ctx, time.Now()); err != nil {
+		log.Printf("could not purge expired revoked tokens: %v", err)
+	}
+	return nil
+}
+
+func (s *authService) Authenticate(ctx context.Context, accessToken string) (*Actor, error) {
+	claims, err := s.tokens.Parse(accessToken, auth.TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNotRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+	user, err := s.activeUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	actor := NewActor(user)
+	actor.TokenID = claims.ID
+	actor.TokenExpiresAt = claims.ExpiresAt.Time
+	return actor, nil
+}
+
+func (s *authService) GetUser(ctx context.Context, id uint) (*models.User, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *authService) ensureNotRevoked(ctx context.Context, claims *auth.Claims) error {
+	revoked, err := s.repo.IsTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return auth.ErrInvalidToken
+	}
+	return nil
+}
+
+// activeUser verifica que el usuario del token siga existiendo y activo, de
+// modo que desactivar una cuenta corte su acceso sin esperar al vencimiento.
+func (s *authService) activeUser(ctx context.Context, id uint) (*models.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !user.IsActive) {
+		return nil, auth.ErrInvalidToken
+	}
+	return user, err
+}
@@ -1,37 +1,634 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/audit"
+	"github.com/toor/backend/internal/models"
 	"github.com/toor/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidNumber indica que el número a anular no pertenece a la serie
+	// o que falta la justificación.
+	ErrInvalidNumber = apperror.Validation("INVALID_NUMBER", "número inválido")
+	// ErrNumberAlreadyVoided indica que el número ya estaba anulado.
+	ErrNumberAlreadyVoided = apperror.Conflict("NUMBER_ALREADY_VOIDED", "el número ya está anulado")
+	// ErrInvalidAdjustment indica un ajuste de contador que retrocede la serie
+	// o que no tiene justificación.
+	ErrInvalidAdjustment = apperror.Validation("INVALID_ADJUSTMENT", "ajuste de contador inválido")
 )
 
 type CounterService interface {
-	GenerateNextID(docType string) (string, error)
-	PerformAnnualReset(newYear int) error
+	// IssueNumber consume el siguiente número del tipo de documento según su
+	// configuración, usando el repositorio de la transacción del documento.
+	// unitID es la unidad solicitante; solo se exige en los tipos que se
+	// numeran por unidad. Devuelve el registro del número sin guardar: el
+	// llamador lo completa y lo guarda en la misma transacción.
+	IssueNumber(ctx context.Context, counters repository.CounterRepository, docType string, unitID *uint) (*models.IssuedNumber, error)
+	CloseFiscalYear(ctx context.Context, actor *Actor, year int) (*FiscalYearClosing, error)
+	GetFiscalYears(ctx context.Context) ([]models.FiscalYear, error)
+	// VoidNumber anula un número de la serie con una justificación.
+	VoidNumber(ctx context.Context, actor *Actor, req VoidNumberRequest) (*models.IssuedNumber, error)
+	// GetNumberGaps informa, por serie, los números consumidos que no están
+	// asignados a un documento ni anulados.
+	GetNumberGaps(ctx context.Context, docType string, year *int) ([]NumberGapReport, error)
+	ListCounters(ctx context.Context) ([]CounterSummary, error)
+	PreviewNumber(ctx context.Context, docType string, unitID *uint) (*NumberPreview, error)
+	AdjustSequence(ctx context.Context, actor *Actor, req SequenceAdjustment) (*NumberPreview, error)
+}
+
+// CounterSummary es un contador con su último número formateado.
+type CounterSummary struct {
+	DocumentType     string `json:"documentType"`
+	Name             string `json:"name"`
+	Year             int    `json:"year"` // 0 en las series que no se reinician
+	UnitID           uint   `json:"unitId,omitempty"`
+	FiscalYearStatus string `json:"fiscalYearStatus,omitempty"`
+	LastSequence     uint   `json:"lastSequence"`
+	LastNumber       string `json:"lastNumber,omitempty"`
+}
+
+// NumberPreview es el estado de una serie en el ejercicio abierto y el
+// número que recibirá el próximo documento.
+type NumberPreview struct {
+	DocumentType string `json:"documentType"`
+	Year         int    `json:"year"`
+	UnitID       uint   `json:"unitId,omitempty"`
+	LastSequence uint   `json:"lastSequence"`
+	NextSequence uint   `json:"nextSequence"`
+	NextNumber   string `json:"nextNumber"`
+}
+
+// SequenceAdjustment pide que la serie continúe en NextSequence.
+type SequenceAdjustment struct {
+	DocumentType string
+	UnitID       *uint // Solo en las series por unidad
+	NextSequence uint
+	Reason       string
+}
+
+// maxSequenceAdjustment limita cuántos números puede saltear un ajuste, para
+// evitar que un error de tipeo llene el registro de números emitidos.
+const maxSequenceAdjustment = 100000
+
+// VoidNumberRequest identifica el número a anular dentro de su serie.
+type VoidNumberRequest struct {
+	DocumentType string
+	Year         int  // 0 en las series que no se reinician
+	UnitID       uint // 0 en las series globales
+	Sequence     uint
+	Reason       string
+}
+
+// NumberGapReport resume el estado de una serie.
+type NumberGapReport struct {
+	DocumentType string      `json:"documentType"`
+	Year         int         `json:"year"`
+	UnitID       uint        `json:"unitId,omitempty"`
+	LastSequence uint        `json:"lastSequence"`
+	Used         int         `json:"used"`
+	Voided       int         `json:"voided"`
+	Failed       int         `json:"failed"`
+	Missing      int         `json:"missing"` // Consumidos sin ningún registro
+	Gaps         []NumberGap `json:"gaps"`
+}
+
+// NumberGap es un número sin justificar: fallido o sin registro.
+type NumberGap struct {
+	Sequence uint   `json:"sequence"`
+	Number   string `json:"number"`
+	Status   string `json:"status"` // FAILED o MISSING
+}
+
+// numberMissing marca en el informe los números sin registro.
+const numberMissing = "MISSING"
+
+// FiscalYearClosing es el resultado del cierre de un ejercicio.
+type FiscalYearClosing struct {
+	FiscalYear models.FiscalYear `json:"fiscalYear"`
+	NewYear    int               `json:"newYear"`
+	// FinalSequences es el último número emitido de cada serie.
+	FinalSequences []FinalSequence `json:"finalSequences"`
+}
+
+type FinalSequence struct {
+	DocumentType string `json:"documentType"`
+	UnitID       uint   `json:"unitId,omitempty"` // Solo en las series por unidad
+	LastSequence uint   `json:"lastSequence"`
+	LastNumber   string `json:"lastNumber,omitempty"` // Vacío si no se emitió ninguno
 }
 
 type counterService struct {
-	repo repository.CounterRepository
+	repo     repository.CounterRepository
+	docTypes repository.DocumentTypeRepository
+	units    repository.MasterDataRepository
+	uow      repository.UnitOfWork
+}
+
+func NewCounterService(repo repository.CounterRepository, docTypes repository.DocumentTypeRepository, units repository.MasterDataRepository, uow repository.UnitOfWork) CounterService {
+	return &counterService{repo: repo, docTypes: docTypes, units: units, uow: uow}
 }
 
-func NewCounterService(repo repository.CounterRepository) CounterService {
-	return &counterService{repo: repo}
+func (s *counterService) IssueNumber(ctx context.Context, counters repository.CounterRepository, code string, unitID *uint) (*models.IssuedNumber, error) {
+	series, err := s.resolveSeries(ctx, code, unitID)
+	if err != nil {
+		return nil, err
+	}
+	year, sequence, err := counters.GetNextSequence(ctx, series.docType.Code, series.unitID, series.yearly())
+	if err != nil {
+		return nil, err
+	}
+	return &models.IssuedNumber{
+		DocumentType: series.docType.Code,
+		Year:         series.counterYear(year),
+		UnitID:       series.unitID,
+		Sequence:     sequence,
+		Number:       series.format(year, sequence),
+		Status:       models.IssuedNumberUsed,
+	}, nil
 }
 
-func (s *counterService) GenerateNextID(docType string) (string, error) {
-	currentYear := time.Now().Year()
-	sequence, err := s.repo.GetNextSequence(docType, currentYear)
+// PreviewNumber devuelve el número que recibiría el próximo documento, sin
+// consumirlo. Otro documento puede tomarlo antes.
+func (s *counterService) PreviewNumber(ctx context.Context, code string, unitID *uint) (*NumberPreview, error) {
+	series, err := s.resolveSeries(ctx, code, unitID)
+	if err != nil {
+		return nil, err
+	}
+	counter, year, err := s.repo.PeekCounter(ctx, series.docType.Code, series.unitID, series.yearly())
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	return series.preview(year, counter.LastSequence), nil
+}
+
+// AdjustSequence hace que la serie continúe en req.NextSequence, por ejemplo
+// al pasar a mitad de año desde el libro de registro en papel. Solo puede
+// avanzar: los números salteados quedan en el registro como usados fuera del
+// sistema y el cambio del contador queda en la bitácora con la justificación.
+func (s *counterService) AdjustSequence(ctx context.Context, actor *Actor, req SequenceAdjustment) (*NumberPreview, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
 	}
-	// Formato: TIPO-AÑO-SECUENCIA (ej. MEMO-2024-00001)
-	return fmt.Sprintf("%s-%d-%05d", docType, currentYear, sequence), nil
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return nil, fmt.Errorf("%w: debe indicar la justificación del ajuste", ErrInvalidAdjustment)
+	}
+	series, err := s.resolveSeries(ctx, req.DocumentType, req.UnitID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = audit.WithReason(actor.Context(ctx), req.Reason)
+	var preview *NumberPreview
+	err = s.uow.Do(ctx, func(tx repository.Repositories) error {
+		counter, year, err := tx.Counters().LockCounter(ctx, series.docType.Code, series.unitID, series.yearly())
+		if err != nil {
+			return err
+		}
+		last := counter.LastSequence
+		switch {
+		case req.NextSequence <= last+1:
+			return fmt.Errorf("%w: la serie ya va por el número %d y solo puede avanzar", ErrInvalidAdjustment, last)
+		case req.NextSequence-last-1 > maxSequenceAdjustment:
+			return fmt.Errorf("%w: el ajuste saltearía más de %d números", ErrInvalidAdjustment, maxSequenceAdjustment)
+		}
+
+		skipped := make([]models.IssuedNumber, 0, req.NextSequence-last-1)
+		for seq := last + 1; seq < req.NextSequence; seq++ {
+			skipped = append(skipped, models.IssuedNumber{
+				DocumentType: series.docType.Code,
+				Year:         counter.CurrentYear,
+				UnitID:       series.unitID,
+				Sequence:     seq,
+				Number:       series.format(year, seq),
+				Status:       models.IssuedNumberUsed,
+				IssuedBy:     actor.Username,
+				Reason:       "Emitido fuera del sistema: " + req.Reason,
+			})
+		}
+		if err := tx.Counters().CreateIssuedNumbers(ctx, skipped); err != nil {
+			return err
+		}
+		counter.LastSequence = req.NextSequence - 1
+		if err := tx.Counters().SaveCounter(ctx, counter); err != nil {
+			return err
+		}
+		preview = series.preview(year, counter.LastSequence)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+// ListCounters devuelve cada contador con su último número y el estado de su
+// ejercicio fiscal.
+func (s *counterService) ListCounters(ctx context.Context) ([]CounterSummary, error) {
+	counters, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	years, err := s.repo.GetFiscalYears(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := map[int]string{}
+	for _, y := range years {
+		status[y.Year] = y.Status
+	}
+	byCode, err := s.documentTypesByCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]CounterSummary, 0, len(counters))
+	for _, c := range counters {
+		summary := CounterSummary{
+			DocumentType:     c.DocumentType,
+			Name:             byCode[c.DocumentType].Name,
+			Year:             c.CurrentYear,
+			UnitID:           c.UnitID,
+			FiscalYearStatus: status[c.CurrentYear],
+			LastSequence:     c.LastSequence,
+		}
+		if c.LastSequence > 0 {
+			summary.LastNumber = formatSeriesNumber(byCode, c.DocumentType, c.CurrentYear, s.seriesUnit(ctx, c.UnitID), c.LastSequence)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// series es la serie de numeración de un tipo de documento para una unidad.
+type series struct {
+	docType *models.DocumentType
+	unitID  uint   // 0 en las series globales
+	unit    string // Abreviatura de la unidad para {UNIT}
+}
+
+// resolveSeries busca la configuración del tipo y, en las series por unidad,
+// la unidad solicitante.
+func (s *counterService) resolveSeries(ctx context.Context, code string, unitID *uint) (*series, error) {
+	docType, err := s.docTypes.GetByCode(ctx, code)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: no existe el tipo de documento %s", ErrNumberingNotConfigured, code)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &series{docType: docType}
+	if docType.Scope == models.NumberingScopeUnit {
+		if unitID == nil {
+			return nil, fmt.Errorf("%w: el tipo %s se numera por unidad y el documento no tiene unidad solicitante", ErrNumberingNotConfigured, code)
+		}
+		unit, err := s.units.GetUnitByID(ctx, *unitID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: la unidad %d no existe", ErrInvalidReference, *unitID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.unitID = unit.ID
+		result.unit = unitCode(unit)
+	}
+	return result, nil
+}
+
+func (x *series) yearly() bool {
+	return x.docType.ResetPolicy != models.ResetPolicyNever
+}
+
+// counterYear es el año con el que se guarda el contador: el del ejercicio,
+// o 0 si la serie no se reinicia.
+func (x *series) counterYear(fiscalYear int) int {
+	if x.yearly() {
+		return fiscalYear
+	}
+	return 0
+}
+
+func (x *series) format(fiscalYear int, sequence uint) string {
+	return formatDocumentNumber(x.docType.Pattern, numberParts{
+		Prefix:   x.docType.Prefix,
+		Unit:     x.unit,
+		Year:     fiscalYear,
+		Sequence: sequence,
+	})
 }
 
-func (s *counterService) PerformAnnualReset(newYear int) error {
-	// La lógica real está en el repositorio, que crea nuevos contadores por año.
-	// El servicio podría añadir lógica adicional, como validar que el newYear sea futuro.
-	return s.repo.ResetAllCounters(newYear)
-}
\ No newline at end of file
+func (x *series) preview(fiscalYear int, lastSequence uint) *NumberPreview {
+	return &NumberPreview{
+		DocumentType: x.docType.Code,
+		Year:         x.counterYear(fiscalYear),
+		UnitID:       x.unitID,
+		LastSequence: lastSequence,
+		NextSequence: lastSequence + 1,
+		NextNumber:   x.format(fiscalYear, lastSequence+1),
+	}
+}
+
+// CloseFiscalYear cierra el ejercicio year y abre year+1. Después del cierre
+// ya no se pueden emitir números del ejercicio cerrado.
+func (s *counterService) CloseFiscalYear(ctx context.Context, actor *Actor, year int) (*FiscalYearClosing, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	byCode, err := s.documentTypesByCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Solo las series que se reinician tienen contador en cada ejercicio; las
+	// series por unidad se abren para cada unidad activa.
+	var yearlyCodes, unitCodes []string
+	for _, dt := range byCode {
+		switch {
+		case dt.ResetPolicy == models.ResetPolicyNever:
+		case dt.Scope == models.NumberingScopeUnit:
+			unitCodes = append(unitCodes, dt.Code)
+		default:
+			yearlyCodes = append(yearlyCodes, dt.Code)
+		}
+	}
+	sort.Strings(yearlyCodes)
+	sort.Strings(unitCodes)
+	var unitIDs []uint
+	if len(unitCodes) > 0 {
+		units, err := s.units.GetAllUnits(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range units {
+			if u.IsActive {
+				unitIDs = append(unitIDs, u.ID)
+			}
+		}
+	}
+
+	// Todo el cierre es una sola transacción: se bloquea el ejercicio, se
+	// marca como cerrado, se crean los contadores que falten para informar la
+	// secuencia final de cada tipo y se abre el siguiente con contadores en cero.
+	var fiscalYear *models.FiscalYear
+	var counters []models.SystemCounter
+	ctx = actor.Context(ctx)
+	err = s.uow.Do(ctx, func(tx repository.Repositories) error {
+		var err error
+		fiscalYear, err = tx.Counters().LockFiscalYear(ctx, year)
+		if err != nil {
+			return err
+		}
+		if fiscalYear.Status == models.FiscalYearClosed {
+			return repository.ErrFiscalYearClosed
+		}
+		now := time.Now()
+		fiscalYear.Status = models.FiscalYearClosed
+		fiscalYear.ClosedAt = &now
+		fiscalYear.ClosedBy = actor.Username
+		if err := tx.Counters().SaveFiscalYear(ctx, fiscalYear); err != nil {
+			return err
+		}
+
+		if err := tx.Counters().EnsureCounters(ctx, year, yearlyCodes, 0); err != nil {
+			return err
+		}
+		if counters, err = tx.Counters().FindCounters(ctx, "", &year); err != nil {
+			return err
+		}
+
+		if err := tx.Counters().OpenFiscalYear(ctx, year+1); err != nil {
+			return err
+		}
+		if err := tx.Counters().EnsureCounters(ctx, year+1, yearlyCodes, 0); err != nil {
+			return err
+		}
+		for _, unitID := range unitIDs {
+			if err := tx.Counters().EnsureCounters(ctx, year+1, unitCodes, unitID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	closing := &FiscalYearClosing{FiscalYear: *fiscalYear, NewYear: year + 1}
+	for _, c := range counters {
+		final := FinalSequence{DocumentType: c.DocumentType, UnitID: c.UnitID, LastSequence: c.LastSequence}
+		if c.LastSequence > 0 {
+			final.LastNumber = formatSeriesNumber(byCode, c.DocumentType, c.CurrentYear, s.seriesUnit(ctx, c.UnitID), c.LastSequence)
+		}
+		closing.FinalSequences = append(closing.FinalSequences, final)
+	}
+	return closing, nil
+}
+
+// documentTypesByCode devuelve la configuración de numeración por código.
+func (s *counterService) documentTypesByCode(ctx context.Context) (map[string]models.DocumentType, error) {
+	docTypes, err := s.docTypes.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]models.DocumentType, len(docTypes))
+	for _, dt := range docTypes {
+		byCode[dt.Code] = dt
+	}
+	return byCode, nil
+}
+
+// formatSeriesNumber arma un número de la serie con la configuración actual
+// del tipo; unit es la abreviatura devuelta por seriesUnit. Si el tipo ya no
+// está configurado se usa el formato por defecto con el código como prefijo.
+//
+// Los contadores de las series que no se reinician tienen año 0, mientras que
+// el número se emitió con el año del ejercicio. Si el patrón usa el año (solo
+// posible en configuraciones anteriores a su validación) el número no puede
+// reconstruirse y se devuelve vacío.
+func formatSeriesNumber(byCode map[string]models.DocumentType, code string, year int, unit string, sequence uint) string {
+	docType, ok := byCode[code]
+	if !ok {
+		docType = models.DocumentType{Prefix: code, Pattern: models.DefaultNumberPattern}
+	}
+	if year == 0 {
+		if tokens, _ := patternTokens(docType.Pattern); tokens["YYYY"] || tokens["YY"] {
+			return ""
+		}
+	}
+	parts := numberParts{Prefix: docType.Prefix, Year: year, Unit: unit, Sequence: sequence}
+	return formatDocumentNumber(docType.Pattern, parts)
+}
+
+// seriesUnit devuelve la abreviatura de la unidad de una serie ("" en las
+// series globales), o su ID si la unidad ya no existe.
+func (s *counterService) seriesUnit(ctx context.Context, unitID uint) string {
+	if unitID == 0 {
+		return ""
+	}
+	if unit, err := s.units.GetUnitByID(ctx, unitID); err == nil {
+		return unitCode(unit)
+	}
+	return fmt.Sprint(unitID)
+}
+
+func (s *counterService) GetFiscalYears(ctx context.Context) ([]models.FiscalYear, error) {
+	return s.repo.GetFiscalYears(ctx)
+}
+
+// VoidNumber anula un número consumido. Un número asignado a una orden solo
+// puede anularse si la orden fue anulada; uno sin registro (consumido antes de
+// llevar el registro) queda registrado como anulado.
+func (s *counterService) VoidNumber(ctx context.Context, actor *Actor, req VoidNumberRequest) (*models.IssuedNumber, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return nil, fmt.Errorf("%w: debe indicar la justificación de la anulación", ErrInvalidNumber)
+	}
+
+	entry, err := s.repo.FindIssuedNumber(ctx, req.DocumentType, req.Year, req.UnitID, req.Sequence)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		entry, err = s.missingNumber(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case entry.Status == models.IssuedNumberVoided:
+		return nil, ErrNumberAlreadyVoided
+	case entry.Status == models.IssuedNumberUsed && entry.OrderID != nil:
+		active, err := s.repo.IsOrderActive(ctx, *entry.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		if active {
+			return nil, newInUseError(fmt.Sprintf("el número %s está asignado a la orden %d; anule la orden antes de anular el número", entry.Number, *entry.OrderID))
+		}
+	}
+
+	now := time.Now()
+	entry.Status = models.IssuedNumberVoided
+	entry.Reason = req.Reason
+	entry.VoidedBy = actor.Username
+	entry.VoidedAt = &now
+	if err := s.repo.SaveIssuedNumber(actor.Context(ctx), entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// missingNumber arma el registro de un número de la serie que se consumió sin
+// quedar registrado. Devuelve ErrInvalidNumber si la serie todavía no llegó a él.
+func (s *counterService) missingNumber(ctx context.Context, req VoidNumberRequest) (*models.IssuedNumber, error) {
+	counters, err := s.repo.FindCounters(ctx, req.DocumentType, &req.Year)
+	if err != nil {
+		return nil, err
+	}
+	var lastSequence uint
+	for _, c := range counters {
+		if c.UnitID == req.UnitID {
+			lastSequence = c.LastSequence
+		}
+	}
+	if req.Sequence == 0 || req.Sequence > lastSequence {
+		return nil, fmt.Errorf("%w: la serie %s %d todavía no emitió el número %d", ErrInvalidNumber, req.DocumentType, req.Year, req.Sequence)
+	}
+	byCode, err := s.documentTypesByCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	number := formatSeriesNumber(byCode, req.DocumentType, req.Year, s.seriesUnit(ctx, req.UnitID), req.Sequence)
+	if number == "" {
+		return nil, fmt.Errorf("%w: no se puede determinar el número %d de la serie %s porque su patrón usa el año", ErrInvalidNumber, req.Sequence, req.DocumentType)
+	}
+	return &models.IssuedNumber{
+		DocumentType: req.DocumentType,
+		Year:         req.Year,
+		UnitID:       req.UnitID,
+		Sequence:     req.Sequence,
+		Number:       number,
+	}, nil
+}
+
+func (s *counterService) GetNumberGaps(ctx context.Context, docType string, year *int) ([]NumberGapReport, error) {
+	counters, err := s.repo.FindCounters(ctx, docType, year)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.FindIssuedNumbers(ctx, docType, year)
+	if err != nil {
+		return nil, err
+	}
+	byCode, err := s.documentTypesByCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type seriesKey struct {
+		docType string
+		year    int
+		unitID  uint
+	}
+	bySeries := map[seriesKey]map[uint]models.IssuedNumber{}
+	for _, e := range entries {
+		key := seriesKey{e.DocumentType, e.Year, e.UnitID}
+		if bySeries[key] == nil {
+			bySeries[key] = map[uint]models.IssuedNumber{}
+		}
+		bySeries[key][e.Sequence] = e
+	}
+
+	reports := make([]NumberGapReport, 0, len(counters))
+	for _, c := range counters {
+		report := NumberGapReport{
+			DocumentType: c.DocumentType,
+			Year:         c.CurrentYear,
+			UnitID:       c.UnitID,
+			LastSequence: c.LastSequence,
+			Gaps:         []NumberGap{},
+		}
+		series := bySeries[seriesKey{c.DocumentType, c.CurrentYear, c.UnitID}]
+		unit := s.seriesUnit(ctx, c.UnitID)
+		for seq := uint(1); seq <= c.LastSequence; seq++ {
+			entry, ok := series[seq]
+			switch {
+			case !ok:
+				report.Missing++
+				report.Gaps = append(report.Gaps, NumberGap{
+					Sequence: seq,
+					Number:   formatSeriesNumber(byCode, c.DocumentType, c.CurrentYear, unit, seq),
+					Status:   numberMissing,
+				})
+			case entry.Status == models.IssuedNumberUsed:
+				report.Used++
+			case entry.Status == models.IssuedNumberVoided:
+				report.Voided++
+			case entry.Status == models.IssuedNumberFailed:
+				report.Failed++
+				report.Gaps = append(report.Gaps, NumberGap{Sequence: seq, Number: entry.Number, Status: entry.Status})
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// unitCode es la abreviatura de la unidad en los números de documento; si la
+// unidad no tiene código se usa su ID.
+func unitCode(unit *models.Unit) string {
+	if unit.Code != "" {
+		return unit.Code
+	}
+	return fmt.Sprint(unit.ID)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+
+	"github.com/toor/backend/internal/documents"
+	"github.com/toor/backend/internal/repository"
+)
+
+type DocumentService interface {
+	// RenderOrderDocument devuelve el PDF y el nombre de archivo sugerido.
+	RenderOrderDocument(ctx context.Context, orderID uint, kind documents.Kind) ([]byte, string, error)
+}
+
+type documentService struct {
+	orderRepo repository.OrderRepository
+}
+
+func NewDocumentService(orderRepo repository.OrderRepository) DocumentService {
+	return &documentService{orderRepo: orderRepo}
+}
+
+func (s *documentService) RenderOrderDocument(ctx context.Context, orderID uint, kind documents.Kind) ([]byte, string, error) {
+	order, err := s.orderRepo.GetOrderById(ctx, orderID)
+	if err != nil {
+		return nil, "", err
+	}
+	pdf, err := documents.Render(kind, order)
+	if err != nil {
+		return nil, "", err
+	}
+	return pdf, documents.FileName(kind, order), nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidDocumentType indica una configuración de numeración incoherente.
+	ErrInvalidDocumentType = apperror.Validation("INVALID_DOCUMENT_TYPE", "tipo de documento inválido")
+	// ErrNumberingNotConfigured indica que no se puede numerar un documento
+	// con la configuración actual (tipo inexistente o sin unidad).
+	ErrNumberingNotConfigured = apperror.Unprocessable("NUMBERING_NOT_CONFIGURED", "la numeración del documento no está configurada")
+)
+
+// workflowDocumentTypes son los tipos que numera el flujo de las órdenes,
+// con su configuración inicial. No pueden eliminarse.
+var workflowDocumentTypes = []models.DocumentType{
+	{Code: models.DocumentTypeMemo, Name: "Memorándum de requisición", Prefix: "MEMO"},
+	{Code: models.DocumentTypeAccountPoint, Name: "Punto de cuenta", Prefix: "PC"},
+	{Code: models.DocumentTypeOrder, Name: "Orden de compra o servicio", Prefix: "OC"},
+}
+
+var documentTypeCode = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+type DocumentTypeService interface {
+	GetAllDocumentTypes(ctx context.Context) ([]models.DocumentType, error)
+	CreateDocumentType(ctx context.Context, actor *Actor, docType *models.DocumentType) (*models.DocumentType, error)
+	UpdateDocumentType(ctx context.Context, actor *Actor, id uint, req *models.DocumentType) (*models.DocumentType, error)
+	DeleteDocumentType(ctx context.Context, actor *Actor, id uint) error
+	// SeedDefaults registra los tipos del flujo de las órdenes que falten.
+	SeedDefaults(ctx context.Context) error
+}
+
+type documentTypeService struct {
+	repo repository.DocumentTypeRepository
+}
+
+func NewDocumentTypeService(repo repository.DocumentTypeRepository) DocumentTypeService {
+	return &documentTypeService{repo: repo}
+}
+
+func (s *documentTypeService) GetAllDocumentTypes(ctx context.Context) ([]models.DocumentType, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *documentTypeService) CreateDocumentType(ctx context.Context, actor *Actor, docType *models.DocumentType) (*models.DocumentType, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	docType.ID = 0
+	docType.Code = strings.ToUpper(strings.TrimSpace(docType.Code))
+	if err := validateDocumentType(docType); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByCode(ctx, docType.Code); err == nil {
+		return nil, fmt.Errorf("%w: ya existe el tipo %s", ErrInvalidDocumentType, docType.Code)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err := s.repo.Create(actor.Context(ctx), docType); err != nil {
+		return nil, err
+	}
+	return docType, nil
+}
+
+// UpdateDocumentType cambia el nombre, el prefijo y el patrón. El código no se
+// modifica, y el alcance y la política de reinicio quedan fijos una vez que
+// se emitió algún número, porque cambiarlos abriría una serie nueva que
+// repetiría números ya emitidos.
+func (s *documentTypeService) UpdateDocumentType(ctx context.Context, actor *Actor, id uint, req *models.DocumentType) (*models.DocumentType, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	docType, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if code := strings.ToUpper(strings.TrimSpace(req.Code)); code != "" && code != docType.Code {
+		return nil, fmt.Errorf("%w: el código no puede modificarse", ErrInvalidDocumentType)
+	}
+	if req.Scope == "" {
+		req.Scope = docType.Scope
+	}
+	if req.ResetPolicy == "" {
+		req.ResetPolicy = docType.ResetPolicy
+	}
+	if req.Scope != docType.Scope || req.ResetPolicy != docType.ResetPolicy {
+		issued, err := s.repo.HasIssuedNumbers(ctx, docType.Code)
+		if err != nil {
+			return nil, err
+		}
+		if issued {
+			return nil, newInUseError("no se puede cambiar el alcance ni la política de reinicio: ya se emitieron números de este tipo")
+		}
+	}
+
+	docType.Name = req.Name
+	docType.Prefix = req.Prefix
+	docType.Pattern = req.Pattern
+	docType.Scope = req.Scope
+	docType.ResetPolicy = req.ResetPolicy
+	if err := validateDocumentType(docType); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(actor.Context(ctx), docType); err != nil {
+		return nil, err
+	}
+	return docType, nil
+}
+
+func (s *documentTypeService) DeleteDocumentType(ctx context.Context, actor *Actor, id uint) error {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return err
+	}
+	docType, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, dt := range workflowDocumentTypes {
+		if dt.Code == docType.Code {
+			return newInUseError("no se puede eliminar el tipo de documento: lo usa el flujo de las órdenes")
+		}
+	}
+	issued, err := s.repo.HasIssuedNumbers(ctx, docType.Code)
+	if err != nil {
+		return err
+	}
+	if issued {
+		return newInUseError("no se puede eliminar el tipo de documento: ya se emitieron números de este tipo")
+	}
+	return s.repo.Delete(actor.Context(ctx), id)
+}
+
+func (s *documentTypeService) SeedDefaults(ctx context.Context) error {
+	created := 0
+	for _, dt := range workflowDocumentTypes {
+		_, err := s.repo.GetByCode(ctx, dt.Code)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		docType := dt
+		docType.Pattern = models.DefaultNumberPattern
+		docType.Scope = models.NumberingScopeGlobal
+		docType.ResetPolicy = models.ResetPolicyYearly
+		if err := s.repo.Create(ctx, &docType); err != nil {
+			return err
+		}
+		created++
+	}
+	if created > 0 {
+		log.Printf("%d default document types created", created)
+	}
+	return nil
+}
+
+// validateDocumentType completa los valores por defecto y verifica que el
+// patrón produzca números únicos dentro de la serie.
+func validateDocumentType(docType *models.DocumentType) error {
+	docType.Name = strings.TrimSpace(docType.Name)
+	docType.Prefix = strings.TrimSpace(docType.Prefix)
+	docType.Pattern = strings.TrimSpace(docType.Pattern)
+	if docType.Pattern == "" {
+		docType.Pattern = models.DefaultNumberPattern
+	}
+	if docType.Scope == "" {
+		docType.Scope = models.NumberingScopeGlobal
+	}
+	if docType.ResetPolicy == "" {
+		docType.ResetPolicy = models.ResetPolicyYearly
+	}
+
+	if !documentTypeCode.MatchString(docType.Code) {
+		return fmt.Errorf("%w: el código debe tener solo mayúsculas, dígitos y guiones bajos", ErrInvalidDocumentType)
+	}
+	if docType.Name == "" {
+		return fmt.Errorf("%w: el nombre es obligatorio", ErrInvalidDocumentType)
+	}
+	if docType.Scope != models.NumberingScopeGlobal && docType.Scope != models.NumberingScopeUnit {
+		return fmt.Errorf("%w: alcance %q desconocido", ErrInvalidDocumentType, docType.Scope)
+	}
+	if docType.ResetPolicy != models.ResetPolicyYearly && docType.ResetPolicy != models.ResetPolicyNever {
+		return fmt.Errorf("%w: política de reinicio %q desconocida", ErrInvalidDocumentType, docType.ResetPolicy)
+	}
+
+	tokens, err := patternTokens(docType.Pattern)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDocumentType, err)
+	}
+	switch {
+	case !tokens["SEQ"]:
+		return fmt.Errorf("%w: el patrón debe incluir {SEQ}", ErrInvalidDocumentType)
+	case tokens["PREFIX"] && docType.Prefix == "":
+		return fmt.Errorf("%w: el patrón usa {PREFIX} y el prefijo está vacío", ErrInvalidDocumentType)
+	case docType.Scope == models.NumberingScopeUnit && !tokens["UNIT"]:
+		return fmt.Errorf("%w: las series por unidad deben incluir {UNIT} en el patrón", ErrInvalidDocumentType)
+	case docType.Scope == models.NumberingScopeGlobal && tokens["UNIT"]:
+		return fmt.Errorf("%w: {UNIT} solo puede usarse en series por unidad", ErrInvalidDocumentType)
+	case docType.ResetPolicy == models.ResetPolicyYearly && !tokens["YYYY"] && !tokens["YY"]:
+		return fmt.Errorf("%w: las series que se reinician cada año deben incluir {YYYY} o {YY}", ErrInvalidDocumentType)
+	case docType.ResetPolicy == models.ResetPolicyNever && (tokens["YYYY"] || tokens["YY"]):
+		// La serie no pertenece a un ejercicio, así que el año no identifica el número.
+		return fmt.Errorf("%w: las series que no se reinician no pueden incluir {YYYY} ni {YY}", ErrInvalidDocumentType)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package service
+
+import "github.com/toor/backend/internal/apperror"
+
+var (
+	// ErrInUse indica que el registro no puede eliminarse porque otros lo referencian.
+	ErrInUse = apperror.InUse("IN_USE", "el registro está en uso")
+	// ErrInvalidReference indica que la orden apunta a un dato maestro inexistente o inactivo.
+	ErrInvalidReference = apperror.Validation("INVALID_REFERENCE", "referencia inválida")
+	// ErrInvalidItem indica un ítem con cantidad o precio fuera de rango.
+	ErrInvalidItem = apperror.Validation("INVALID_ITEM", "ítem inválido")
+	// ErrForbidden indica que el usuario no tiene permiso para la operación.
+	ErrForbidden = apperror.Forbidden("FORBIDDEN", "no tiene permiso para realizar esta operación")
+)
+
+// newInUseError conserva el mensaje de negocio para el cliente y permite
+// reconocer el caso con errors.Is(err, ErrInUse).
+func newInUseError(message string) error {
+	return ErrInUse.WithMessage(message)
+}
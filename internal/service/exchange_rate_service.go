@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/repository"
+)
+
+var (
+	// ErrInvalidExchangeRate indica una tasa con moneda, fecha o valor inválidos.
+	ErrInvalidExchangeRate = apperror.Validation("INVALID_EXCHANGE_RATE", "tasa de cambio inválida")
+	// ErrExchangeRateNotFound indica que no hay tasa cargada para convertir la orden.
+	ErrExchangeRateNotFound = apperror.Unprocessable("EXCHANGE_RATE_NOT_FOUND", "no hay tasa de cambio cargada para la fecha")
+)
+
+// foreignCurrencies son las monedas que requieren tasa de cambio.
+var foreignCurrencies = map[string]bool{
+	models.CurrencyUSD: true,
+	models.CurrencyEUR: true,
+}
+
+type ExchangeRateService interface {
+	CreateExchangeRate(ctx context.Context, actor *Actor, rate *models.ExchangeRate) (*models.ExchangeRate, error)
+	GetAllExchangeRates(ctx context.Context, currency string) ([]models.ExchangeRate, error)
+	UpdateExchangeRate(ctx context.Context, actor *Actor, id uint, req *models.ExchangeRate) (*models.ExchangeRate, error)
+	DeleteExchangeRate(ctx context.Context, actor *Actor, id uint) error
+	// ImportCSV carga tasas desde un CSV con encabezado date,currency,rate[,source].
+	// Las tasas de una fecha y moneda ya cargadas se reemplazan.
+	ImportCSV(ctx context.Context, actor *Actor, r io.Reader) ([]models.ExchangeRate, error)
+}
+
+type exchangeRateService struct {
+	repo repository.ExchangeRateRepository
+}
+
+func NewExchangeRateService(repo repository.ExchangeRateRepository) ExchangeRateService {
+	return &exchangeRateService{repo: repo}
+}
+
+func (s *exchangeRateService) CreateExchangeRate(ctx context.Context, actor *Actor, rate *models.ExchangeRate) (*models.ExchangeRate, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	rate.ID = 0
+	if err := validateExchangeRate(rate); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *exchangeRateService) GetAllExchangeRates(ctx context.Context, currency string) ([]models.ExchangeRate, error) {
+	return s.repo.GetAll(ctx, strings.ToUpper(currency))
+}
+
+func (s *exchangeRateService) UpdateExchangeRate(ctx context.Context, actor *Actor, id uint, req *models.ExchangeRate) (*models.ExchangeRate, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	rate, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rate.Date = req.Date
+	rate.Currency = req.Currency
+	rate.Rate = req.Rate
+	rate.Source = req.Source
+	if err := validateExchangeRate(rate); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *exchangeRateService) DeleteExchangeRate(ctx context.Context, actor *Actor, id uint) error {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *exchangeRateService) ImportCSV(ctx context.Context, actor *Actor, r io.Reader) ([]models.ExchangeRate, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: no se pudo leer el encabezado del CSV", ErrInvalidExchangeRate)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"date", "currency", "rate"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: falta la columna %q", ErrInvalidExchangeRate, required)
+		}
+	}
+	column := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rates []models.ExchangeRate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: línea %d: %v", ErrInvalidExchangeRate, line, err)
+		}
+
+		date, err := time.Parse("2006-01-02", column(record, "date"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: línea %d: fecha inválida (use AAAA-MM-DD)", ErrInvalidExchangeRate, line)
+		}
+		value, err := decimal.NewFromString(column(record, "rate"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: línea %d: tasa inválida", ErrInvalidExchangeRate, line)
+		}
+		rate := models.ExchangeRate{
+			Date:     date,
+			Currency: column(record, "currency"),
+			Rate:     value,
+			Source:   column(record, "source"),
+		}
+		if err := validateExchangeRate(&rate); err != nil {
+			return nil, fmt.Errorf("línea %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: el CSV no contiene tasas", ErrInvalidExchangeRate)
+	}
+
+	if err := s.repo.SaveAll(ctx, rates); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func validateExchangeRate(rate *models.ExchangeRate) error {
+	rate.Currency = strings.ToUpper(strings.TrimSpace(rate.Currency))
+	if !foreignCurrencies[rate.Currency] {
+		return fmt.Errorf("%w: moneda %q no admitida", ErrInvalidExchangeRate, rate.Currency)
+	}
+	if rate.Date.IsZero() {
+		return fmt.Errorf("%w: la fecha es obligatoria", ErrInvalidExchangeRate)
+	}
+	if !rate.Rate.IsPositive() {
+		return fmt.Errorf("%w: la tasa debe ser mayor que cero", ErrInvalidExchangeRate)
+	}
+	return nil
+}
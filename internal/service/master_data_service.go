@@ -1,32 +1,43 @@
 package service
 
 import (
-	"errors" // <-- AÑADIR IMPORT
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
 
+	"github.com/toor/backend/internal/apperror"
 	"github.com/toor/backend/internal/models"
 	"github.com/toor/backend/internal/repository"
 )
 
+// ErrInvalidUnit indica una unidad con datos inválidos.
+var ErrInvalidUnit = apperror.Validation("INVALID_UNIT", "unidad inválida")
+
+// unitCodePattern es la forma del código de la unidad, que aparece en los
+// números de las series por unidad.
+var unitCodePattern = regexp.MustCompile(`^[A-Z0-9]{1,10}$`)
+
 // Interfaces separadas para claridad, implementadas por un solo servicio.
 type UnitService interface {
-	CreateUnit(unit *models.Unit) (*models.Unit, error)
-	GetAllUnits() ([]models.Unit, error)
-	UpdateUnit(id uint, req *models.Unit) (*models.Unit, error)
-	DeleteUnit(id uint) error
+	CreateUnit(ctx context.Context, actor *Actor, unit *models.Unit) (*models.Unit, error)
+	GetAllUnits(ctx context.Context) ([]models.Unit, error)
+	UpdateUnit(ctx context.Context, actor *Actor, id uint, req *models.Unit) (*models.Unit, error)
+	DeleteUnit(ctx context.Context, actor *Actor, id uint) error
 }
 
 type MasterDataService interface {
 	UnitService // Embeber la interfaz
 	// Positions
-	CreatePosition(pos *models.Position) (*models.Position, error)
-	GetAllPositions() ([]models.Position, error)
-	UpdatePosition(id uint, req *models.Position) (*models.Position, error)
-	DeletePosition(id uint) error
+	CreatePosition(ctx context.Context, actor *Actor, pos *models.Position) (*models.Position, error)
+	GetAllPositions(ctx context.Context) ([]models.Position, error)
+	UpdatePosition(ctx context.Context, actor *Actor, id uint, req *models.Position) (*models.Position, error)
+	DeletePosition(ctx context.Context, actor *Actor, id uint) error
 	// Officials
-	CreateOfficial(off *models.Official) (*models.Official, error)
-	GetAllOfficials() ([]models.Official, error)
-	UpdateOfficial(id uint, req *models.Official) (*models.Official, error)
-	DeleteOfficial(id uint) error
+	CreateOfficial(ctx context.Context, actor *Actor, off *models.Official) (*models.Official, error)
+	GetAllOfficials(ctx context.Context) ([]models.Official, error)
+	UpdateOfficial(ctx context.Context, actor *Actor, id uint, req *models.Official) (*models.Official, error)
+	DeleteOfficial(ctx context.Context, actor *Actor, id uint) error
 }
 
 type masterDataService struct {
@@ -38,69 +49,125 @@ func NewMasterDataService(repo repository.MasterDataRepository) MasterDataServic
 }
 
 // Implementaciones...
-func (s *masterDataService) CreateUnit(unit *models.Unit) (*models.Unit, error) {
-	err := s.repo.CreateUnit(unit)
+func (s *masterDataService) CreateUnit(ctx context.Context, actor *Actor, unit *models.Unit) (*models.Unit, error) {
+	if err := authorize(actor, models.PermMasterDataWrite); err != nil {
+		return nil, err
+	}
+	if err := validateUnit(unit); err != nil {
+		return nil, err
+	}
+	err := s.repo.CreateUnit(actor.Context(ctx), unit)
 	return unit, err
 }
-func (s *masterDataService) GetAllUnits() ([]models.Unit, error) { return s.repo.GetAllUnits() }
-func (s *masterDataService) UpdateUnit(id uint, req *models.Unit) (*models.Unit, error) {
+func (s *masterDataService) GetAllUnits(ctx context.Context) ([]models.Unit, error) {
+	return s.repo.GetAllUnits(ctx)
+}
+func (s *masterDataService) UpdateUnit(ctx context.Context, actor *Actor, id uint, req *models.Unit) (*models.Unit, error) {
+	if err := authorize(actor, models.PermMasterDataWrite); err != nil {
+		return nil, err
+	}
 	req.ID = id
-	err := s.repo.UpdateUnit(req)
+	if err := validateUnit(req); err != nil {
+		return nil, err
+	}
+	err := s.repo.UpdateUnit(actor.Context(ctx), req)
 	return req, err
 }
-func (s *masterDataService) DeleteUnit(id uint) error {
-	inUse, err := s.repo.IsUnitInUse(id)
+
+// validateUnit normaliza el código de la unidad y verifica que sea válido. El
+// código es obligatorio porque identifica a la unidad en la numeración.
+func validateUnit(unit *models.Unit) error {
+	unit.Code = strings.ToUpper(strings.TrimSpace(unit.Code))
+	if !unitCodePattern.MatchString(unit.Code) {
+		return fmt.Errorf("%w: el código es obligatorio y debe tener hasta 10 letras o dígitos", ErrInvalidUnit)
+	}
+	return nil
+}
+
+func (s *masterDataService) DeleteUnit(ctx context.Context, actor *Actor, id uint) error {
+	if err := authorize(actor, models.PermMasterDataWrite); err != nil {
+		return err
+	}
+	inUse, err := s.repo.IsUnitInUse(ctx, id)
 	if err != nil {
 		return err // Error al consultar la base de datos
 	}
 	if inUse {
 		// Retornamos un error de negocio específico
-		return errors.New("no se puede eliminar la unidad: está asignada a uno o más funcionarios")
+		return newInUseError("no se puede eliminar la unidad: está asignada a uno o más funcionarios")
+	}
+	inUse, err = s.repo.IsUnitReferencedByOrders(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return newInUseError("no se puede eliminar la unidad: está asignada a una o más órdenes")
 	}
-	return s.repo.DeleteUnit(id)
+	return s.repo.DeleteUnit(actor.Context(ctx), id)
 }
 
-func (s *masterDataService) CreatePosition(pos *models.Position) (*models.Position, error) {
-	err := s.repo.CreatePosition(pos)
+func (s *masterDataService) CreatePosition(ctx context.Context, actor *Actor, pos *models.Position) (*models.Position, error) {
+	if err := authorize(actor, models.PermMasterDataWrite); err != nil {
+		return nil, err
+	}
+	err := s.repo.CreatePosition(actor.Context(ctx), pos)
 	return pos, err
 }
-func (s *masterDataService) GetAllPositions() ([]models.Position, error) {
-	return s.repo.GetAllPositions()
+func (s *masterDataService) GetAllPositions(ctx context.Context) ([]models.Position, error) {
+	return s.repo.GetAllPositions(ctx)
 }
-func (s *masterDataService) UpdatePosition(id uint, req *models.Position) (*models.Position, error) {
+func (s *masterDataService) UpdatePosition(ctx context.Context, actor *Actor, id uint, req *models.Position) (*models.Position, error) {
+	if err := authorize(actor, models.PermMasterDataWrite); err != nil {
+		return nil, err
+	}
 	req.ID = id
-	err := s.repo.UpdatePosition(req)
+	err := s.repo.UpdatePosition(actor.Context(ctx), req)
 	return req, err
 }
-func (s *masterDataService) DeletePosition(id uint) error {
-	inUse, err := s.repo.IsPositionInUse(id)
+func (s *masterDataService) DeletePosition(ctx context.Context, actor *Actor, id uint) error {
+	if err := authorize(actor, models.PermMasterDataWrite); err != nil {
+		return err
+	}
+	inUse, err := s.repo.IsPositionInUse(ctx, id)
 	if err != nil {
 		return err
 	}
 	if inUse {
-		return errors.New("no se puede eliminar el cargo: está asignado a uno o más funcionarios")
+		return newInUseError("no se puede eliminar el cargo: está asignado a uno o más funcionarios")
 	}
-	return s.repo.DeletePosition(id)
+	return s.repo.DeletePosition(actor.Context(ctx), id)
 }
-func (s *masterDataService) CreateOfficial(off *models.Official) (*models.Official, error) {
-	err := s.repo.CreateOfficial(off)
+func (s *masterDataService) CreateOfficial(ctx context.Context, actor *Actor, off *models.Official) (*models.Official, error) {
+	if err := authorize(actor, models.PermMasterDataWrite); err != nil {
+		return nil, err
+	}
+	err := s.repo.CreateOfficial(actor.Context(ctx), off)
 	return off, err
 }
-func (s *masterDataService) GetAllOfficials() ([]models.Official, error) {
-	return s.repo.GetAllOfficials()
+func (s *masterDataService) GetAllOfficials(ctx context.Context) ([]models.Official, error) {
+	return s.repo.GetAllOfficials(ctx)
 }
-func (s *masterDataService) UpdateOfficial(id uint, req *models.Official) (*models.Official, error) {
+func (s *masterDataService) UpdateOfficial(ctx context.Context, actor *Actor, id uint, req *models.Official) (*models.Official, error) {
+	if err := authorize(actor, models.PermMasterDataWrite); err != nil {
+		return nil, err
+	}
 	req.ID = id
-	err := s.repo.UpdateOfficial(req)
-	// Recargar para obtener los datos de Unit y Position
-	updated, _ := s.repo.GetAllOfficials() // Simplificación, idealmente sería GetByID
-	for _, o := range updated {
-		if o.ID == id {
-			return &o, err
-		}
+	if err := s.repo.UpdateOfficial(actor.Context(ctx), req); err != nil {
+		return req, err
 	}
-	return req, err
+	// Recargar para obtener los datos de Unit y Position
+	return s.repo.GetOfficialByID(ctx, id)
 }
-func (s *masterDataService) DeleteOfficial(id uint) error {
-	return s.repo.DeleteOfficial(id)
+func (s *masterDataService) DeleteOfficial(ctx context.Context, actor *Actor, id uint) error {
+	if err := authorize(actor, models.PermMasterDataWrite); err != nil {
+		return err
+	}
+	inUse, err := s.repo.IsOfficialReferencedByOrders(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return newInUseError("no se puede eliminar el funcionario: es responsable de una o más órdenes")
+	}
+	return s.repo.DeleteOfficial(actor.Context(ctx), id)
 }
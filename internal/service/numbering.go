@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// numberToken reconoce los tokens del patrón de numeración: {NOMBRE} o {NOMBRE:n}.
+var numberToken = regexp.MustCompile(`\{([A-Z]+)(?::(\d+))?\}`)
+
+// maxSequenceDigits limita el relleno con ceros de {SEQ:n}.
+const maxSequenceDigits = 12
+
+// numberParts son los valores con los que se reemplazan los tokens.
+type numberParts struct {
+	Prefix   string
+	Unit     string
+	Year     int
+	Sequence uint
+}
+
+// formatDocumentNumber arma el número de un documento a partir del patrón
+// (ej. "{PREFIX}-{UNIT}-{YYYY}-{SEQ:5}" → "OC-SG-2025-00012"). El patrón
+// debe haberse validado con validatePattern.
+func formatDocumentNumber(pattern string, parts numberParts) string {
+	return numberToken.ReplaceAllStringFunc(pattern, func(token string) string {
+		match := numberToken.FindStringSubmatch(token)
+		switch match[1] {
+		case "PREFIX":
+			return parts.Prefix
+		case "UNIT":
+			return parts.Unit
+		case "YYYY":
+			return fmt.Sprintf("%04d", parts.Year)
+		case "YY":
+			return fmt.Sprintf("%02d", parts.Year%100)
+		case "SEQ":
+			digits, _ := strconv.Atoi(match[2])
+			return fmt.Sprintf("%0*d", digits, parts.Sequence)
+		}
+		return token
+	})
+}
+
+// patternTokens devuelve los nombres de los tokens presentes en el patrón,
+// o un error si alguno es desconocido o está mal formado.
+func patternTokens(pattern string) (map[string]bool, error) {
+	tokens := map[string]bool{}
+	for _, match := range numberToken.FindAllStringSubmatch(pattern, -1) {
+		name, width := match[1], match[2]
+		switch name {
+		case "PREFIX", "UNIT", "YYYY", "YY":
+			if width != "" {
+				return nil, fmt.Errorf("el token {%s} no admite cantidad de dígitos", name)
+			}
+		case "SEQ":
+			if digits, _ := strconv.Atoi(width); digits > maxSequenceDigits {
+				return nil, fmt.Errorf("la secuencia admite como máximo %d dígitos", maxSequenceDigits)
+			}
+		default:
+			return nil, fmt.Errorf("token {%s} desconocido", name)
+		}
+		tokens[name] = true
+	}
+	// Cualquier llave que quede fuera de un token indica un error de escritura.
+	if rest := numberToken.ReplaceAllString(pattern, ""); strings.ContainsAny(rest, "{}") {
+		return nil, fmt.Errorf("el patrón %q tiene llaves sin cerrar o tokens mal formados", pattern)
+	}
+	return tokens, nil
+}
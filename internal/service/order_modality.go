@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/money"
+	"github.com/toor/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// modalityCheckedStatuses son los estados a los que no puede pasar una orden
+// cuya modalidad no corresponda a su monto.
+var modalityCheckedStatuses = map[string]bool{
+	models.OrderStatusAccountPoint: true,
+	models.OrderStatusApproved:     true,
+}
+
+// determineModality expresa el total en bolívares de la orden en unidades
+// tributarias y busca la modalidad que corresponde según los umbrales de su
+// tipo de contratación. Si la orden aún no tiene modalidad elegida se le
+// asigna la calculada; si tiene otra (salvo contratación directa) se marca
+// ModalityMismatch. Sin UT o umbrales configurados la modalidad queda vacía.
+func (s *orderService) determineModality(ctx context.Context, order *models.Order) error {
+	if order.ContractType == "" {
+		order.ContractType = models.ContractTypeGoods
+	}
+	if !contractTypes[order.ContractType] {
+		return fmt.Errorf("%w: %q", ErrInvalidContractType, order.ContractType)
+	}
+
+	order.TaxUnitValue = money.Zero
+	order.AmountUT = decimal.Zero
+	order.RequiredModality = ""
+	order.ModalityMismatch = false
+
+	taxUnit, err := s.procurementRepo.FindEffectiveTaxUnit(ctx, taxDate(order))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	thresholds, err := s.procurementRepo.GetThresholdsByContractType(ctx, order.ContractType)
+	if err != nil {
+		return err
+	}
+
+	order.TaxUnitValue = taxUnit.Value
+	order.AmountUT = order.TotalAmountVES.Decimal().Div(taxUnit.Value.Decimal()).Round(2)
+	for _, t := range thresholds {
+		if t.MaxUT == nil || order.AmountUT.LessThanOrEqual(*t.MaxUT) {
+			order.RequiredModality = t.Modality
+			break
+		}
+	}
+	if order.RequiredModality == "" {
+		return nil
+	}
+
+	chosen := strings.TrimSpace(order.PriceInquiryType)
+	if chosen == "" {
+		order.PriceInquiryType = order.RequiredModality
+		return nil
+	}
+	order.ModalityMismatch = !strings.EqualFold(chosen, order.RequiredModality) &&
+		!strings.EqualFold(chosen, models.ModalityDirect)
+	return nil
+}
+
+// ensureModality impide llevar al punto de cuenta o aprobar una orden cuya
+// modalidad no pudo determinarse o no corresponde a su monto. Los montos
+// recalculados se guardan con orders, el repositorio de la transacción de la
+// transición.
+func (s *orderService) ensureModality(ctx context.Context, orders repository.OrderRepository, order *models.Order, toStatus string) error {
+	if !modalityCheckedStatuses[toStatus] {
+		return nil
+	}
+	// Se recalcula por si cambió la UT o los umbrales desde la última edición.
+	if err := s.determineModality(ctx, order); err != nil {
+		return err
+	}
+	if err := orders.UpdateTotals(ctx, order); err != nil {
+		return err
+	}
+	if strings.EqualFold(strings.TrimSpace(order.PriceInquiryType), models.ModalityDirect) {
+		return nil
+	}
+	if order.RequiredModality == "" {
+		return ErrProcurementNotConfigured
+	}
+	if order.ModalityMismatch {
+		return fmt.Errorf("%w: %s %s UT requiere %q y se eligió %q",
+			ErrModalityMismatch, strings.ToLower(order.ContractType), order.AmountUT.StringFixed(2),
+			order.RequiredModality, order.PriceInquiryType)
+	}
+	return nil
+}
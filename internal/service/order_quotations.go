@@ -0,0 +1,386 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MinQuotations es la cantidad mínima de cotizaciones que exige una consulta de precios.
+const MinQuotations = 3
+
+// MaxQualityScore es la puntuación máxima de calidad que asigna el analista.
+const MaxQualityScore = 5
+
+var (
+	// ErrInvalidQuotation indica una cotización con monto o puntuación fuera de rango.
+	ErrInvalidQuotation = apperror.Validation("INVALID_QUOTATION", "cotización inválida")
+	// ErrDuplicateQuotation indica que el proveedor ya cotizó para la orden.
+	ErrDuplicateQuotation = apperror.Conflict("DUPLICATE_QUOTATION", "el proveedor ya tiene una cotización registrada para esta orden")
+	// ErrNotEnoughQuotations indica que aún no hay cotizaciones suficientes para seleccionar una.
+	ErrNotEnoughQuotations = apperror.Unprocessable("NOT_ENOUGH_QUOTATIONS", fmt.Sprintf("se requieren al menos %d cotizaciones para seleccionar la ganadora", MinQuotations))
+)
+
+// QuotationWeights son los pesos (en puntos sobre 100) de cada criterio del cuadro comparativo.
+type QuotationWeights struct {
+	Price    int64 `json:"price"`
+	Delivery int64 `json:"delivery"`
+	Quality  int64 `json:"quality"`
+}
+
+// quotationWeights: el precio pesa más, como establece la consulta de precios.
+var quotationWeights = QuotationWeights{Price: 60, Delivery: 20, Quality: 20}
+
+// QuotationRanking es una fila del cuadro comparativo. Cada puntaje va de 0 a 100.
+type QuotationRanking struct {
+	Rank          int              `json:"rank"`
+	Quotation     models.Quotation `json:"quotation"`
+	PriceScore    decimal.Decimal  `json:"priceScore"`
+	DeliveryScore decimal.Decimal  `json:"deliveryScore"`
+	QualityScore  decimal.Decimal  `json:"qualityScore"`
+	TotalScore    decimal.Decimal  `json:"totalScore"`
+}
+
+// QuotationComparison es el cuadro comparativo de las cotizaciones de una orden.
+type QuotationComparison struct {
+	OrderID       uint               `json:"orderId"`
+	MemoNumber    string             `json:"memoNumber"`
+	MinQuotations int                `json:"minQuotations"`
+	MeetsMinimum  bool               `json:"meetsMinimum"`
+	Weights       QuotationWeights   `json:"weights"`
+	Ranking       []QuotationRanking `json:"ranking"`
+	RecommendedID *uint              `json:"recommendedId"` // Primera del ranking
+	SelectedID    *uint              `json:"selectedId"`
+}
+
+func (s *orderService) GetOrderQuotations(ctx context.Context, orderID uint) ([]models.Quotation, error) {
+	if _, err := s.repo.GetOrderById(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.quotationRepo.GetByOrder(ctx, orderID)
+}
+
+func (s *orderService) AddOrderQuotation(ctx context.Context, actor *Actor, orderID uint, quotation *models.Quotation) (*models.Quotation, error) {
+	if err := authorize(actor, models.PermOrdersWrite); err != nil {
+		return nil, err
+	}
+	quotation.ID = 0
+	quotation.OrderID = orderID
+	quotation.Selected = false
+	ctx = actor.Context(ctx)
+	err := s.uow.Do(ctx, func(tx repository.Repositories) error {
+		if _, err := editableOrder(ctx, tx.Orders(), orderID); err != nil {
+			return err
+		}
+		if err := s.prepareQuotation(ctx, tx.Quotations(), quotation); err != nil {
+			return err
+		}
+		return tx.Quotations().Create(ctx, quotation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.quotationRepo.GetByID(ctx, orderID, quotation.ID)
+}
+
+func (s *orderService) UpdateOrderQuotation(ctx context.Context, actor *Actor, orderID, quotationID uint, req *models.Quotation) (*models.Quotation, error) {
+	if err := authorize(actor, models.PermOrdersWrite); err != nil {
+		return nil, err
+	}
+	ctx = actor.Context(ctx)
+	err := s.uow.Do(ctx, func(tx repository.Repositories) error {
+		if _, err := editableOrder(ctx, tx.Orders(), orderID); err != nil {
+			return err
+		}
+		quotation, err := tx.Quotations().GetByID(ctx, orderID, quotationID)
+		if err != nil {
+			return err
+		}
+		quotation.ProviderID = req.ProviderID
+		quotation.DocumentType = req.DocumentType
+		quotation.BudgetNumber = req.BudgetNumber
+		quotation.BudgetDate = req.BudgetDate
+		quotation.Currency = req.Currency
+		quotation.Amount = req.Amount
+		quotation.DeliveryTime = req.DeliveryTime
+		quotation.DeliveryDays = req.DeliveryDays
+		quotation.OfferQuality = req.OfferQuality
+		quotation.QualityScore = req.QualityScore
+		quotation.Notes = req.Notes
+		if err := s.prepareQuotation(ctx, tx.Quotations(), quotation); err != nil {
+			return err
+		}
+		return tx.Quotations().Update(ctx, quotation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.quotationRepo.GetByID(ctx, orderID, quotationID)
+}
+
+// ScoreOrderQuotation registra la evaluación del analista sobre el tiempo de
+// entrega y la calidad de la oferta.
+func (s *orderService) ScoreOrderQuotation(ctx context.Context, actor *Actor, orderID, quotationID uint, deliveryDays, qualityScore int) (*models.Quotation, error) {
+	if err := authorize(actor, models.PermOrdersWrite); err != nil {
+		return nil, err
+	}
+	var quotation *models.Quotation
+	ctx = actor.Context(ctx)
+	err := s.uow.Do(ctx, func(tx repository.Repositories) error {
+		if _, err := editableOrder(ctx, tx.Orders(), orderID); err != nil {
+			return err
+		}
+		var err error
+		quotation, err = tx.Quotations().GetByID(ctx, orderID, quotationID)
+		if err != nil {
+			return err
+		}
+		quotation.DeliveryDays = deliveryDays
+		quotation.QualityScore = qualityScore
+		if err := validateQuotationScore(quotation); err != nil {
+			return err
+		}
+		return tx.Quotations().Update(ctx, quotation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quotation, nil
+}
+
+func (s *orderService) DeleteOrderQuotation(ctx context.Context, actor *Actor, orderID, quotationID uint) error {
+	if err := authorize(actor, models.PermOrdersWrite); err != nil {
+		return err
+	}
+	ctx = actor.Context(ctx)
+	return s.uow.Do(ctx, func(tx repository.Repositories) error {
+		if _, err := editableOrder(ctx, tx.Orders(), orderID); err != nil {
+			return err
+		}
+		return tx.Quotations().Delete(ctx, orderID, quotationID)
+	})
+}
+
+// CompareOrderQuotations arma el cuadro comparativo: puntúa cada cotización
+// por precio (en bolívares), tiempo de entrega y calidad, y las ordena de
+// mayor a menor puntaje total.
+func (s *orderService) CompareOrderQuotations(ctx context.Context, orderID uint) (*QuotationComparison, error) {
+	order, err := s.repo.GetOrderById(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	quotations, err := s.quotationRepo.GetByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	minimum := minQuotations(order)
+	comparison := &QuotationComparison{
+		OrderID:       order.ID,
+		MemoNumber:    order.MemoNumber,
+		MinQuotations: minimum,
+		MeetsMinimum:  len(quotations) >= minimum,
+		Weights:       quotationWeights,
+		Ranking:       rankQuotations(quotations),
+	}
+	if len(comparison.Ranking) > 0 {
+		id := comparison.Ranking[0].Quotation.ID
+		comparison.RecommendedID = &id
+	}
+	for _, q := range quotations {
+		if q.Selected {
+			id := q.ID
+			comparison.SelectedID = &id
+		}
+	}
+	return comparison, nil
+}
+
+// minQuotations devuelve la cantidad mínima de cotizaciones de la orden. Solo
+// la consulta de precios la exige; si la orden no tiene modalidad elegida se
+// usa la que corresponde a su monto.
+func minQuotations(order *models.Order) int {
+	modality := strings.TrimSpace(order.PriceInquiryType)
+	if modality == "" {
+		modality = order.RequiredModality
+	}
+	if strings.EqualFold(modality, models.ModalityPriceInquiry) {
+		return MinQuotations
+	}
+	return 0
+}
+
+// SelectOrderQuotation marca la cotización ganadora y copia sus datos al
+// paso 2 de la orden (proveedor, presupuesto, moneda, entrega y calidad).
+func (s *orderService) SelectOrderQuotation(ctx context.Context, actor *Actor, orderID, quotationID uint) (*models.Order, error) {
+	if err := authorize(actor, models.PermOrdersWrite); err != nil {
+		return nil, err
+	}
+	// Los datos de la orden y la marca de la cotización se guardan juntos.
+	var updated *models.Order
+	ctx = actor.Context(ctx)
+	err := s.uow.Do(ctx, func(tx repository.Repositories) error {
+		order, err := editableOrder(ctx, tx.Orders(), orderID)
+		if err != nil {
+			return err
+		}
+		quotations, err := tx.Quotations().GetByOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		var selected *models.Quotation
+		for i := range quotations {
+			if quotations[i].ID == quotationID {
+				selected = &quotations[i]
+			}
+		}
+		if selected == nil {
+			return repository.ErrQuotationNotFound
+		}
+		if len(quotations) < minQuotations(order) {
+			return ErrNotEnoughQuotations
+		}
+
+		input := *order
+		providerID := selected.ProviderID
+		input.ProviderID = &providerID
+		input.DocumentType = selected.DocumentType
+		input.BudgetNumber = selected.BudgetNumber
+		input.BudgetDate = selected.BudgetDate
+		input.Currency = selected.Currency
+		input.DeliveryTime = selected.DeliveryTime
+		input.OfferQuality = selected.OfferQuality
+		if updated, err = s.updateOrder(ctx, tx.Orders(), orderID, &input); err != nil {
+			return err
+		}
+		return tx.Quotations().MarkSelected(ctx, orderID, quotationID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// prepareQuotation valida la cotización y calcula su monto en bolívares con
+// la tasa vigente a la fecha del presupuesto. quotations es el repositorio de
+// la transacción en curso, para que la verificación de duplicados vea sus cambios.
+func (s *orderService) prepareQuotation(ctx context.Context, quotations repository.QuotationRepository, quotation *models.Quotation) error {
+	provider, err := s.providerRepo.GetByID(ctx, quotation.ProviderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !provider.IsActive) {
+		return fmt.Errorf("%w: el proveedor %d no existe o está inactivo", ErrInvalidReference, quotation.ProviderID)
+	}
+	if err != nil {
+		return err
+	}
+	existing, err := quotations.GetByOrder(ctx, quotation.OrderID)
+	if err != nil {
+		return err
+	}
+	for _, q := range existing {
+		if q.ProviderID == quotation.ProviderID && q.ID != quotation.ID {
+			return ErrDuplicateQuotation
+		}
+	}
+
+	if !quotation.Amount.Decimal().IsPositive() {
+		return fmt.Errorf("%w: el monto debe ser mayor que cero", ErrInvalidQuotation)
+	}
+	if err := validateQuotationScore(quotation); err != nil {
+		return err
+	}
+
+	if quotation.Currency == "" {
+		quotation.Currency = models.CurrencyVES
+	}
+	quotation.ExchangeRate = decimal.NewFromInt(1)
+	if quotation.Currency != models.CurrencyVES {
+		date := quotation.BudgetDate
+		if date.IsZero() {
+			date = time.Now()
+		}
+		rate, err := s.findExchangeRate(ctx, quotation.Currency, date)
+		if err != nil {
+			return err
+		}
+		quotation.ExchangeRate = rate.Rate
+	}
+	quotation.AmountVES = quotation.Amount.Mul(quotation.ExchangeRate)
+	return nil
+}
+
+func validateQuotationScore(quotation *models.Quotation) error {
+	if quotation.DeliveryDays < 0 {
+		return fmt.Errorf("%w: los días de entrega no pueden ser negativos", ErrInvalidQuotation)
+	}
+	if quotation.QualityScore < 0 || quotation.QualityScore > MaxQualityScore {
+		return fmt.Errorf("%w: la calidad debe puntuarse de 0 a %d", ErrInvalidQuotation, MaxQualityScore)
+	}
+	return nil
+}
+
+// rankQuotations puntúa las cotizaciones de 0 a 100 en cada criterio:
+//   - Precio: el menor monto en bolívares obtiene 100 y el resto en proporción inversa.
+//   - Entrega: el menor plazo obtiene 100 y el resto en proporción inversa;
+//     sin plazo informado, 0.
+//   - Calidad: la puntuación del analista llevada a escala de 100.
+//
+// El total es el promedio ponderado con quotationWeights. Los empates se
+// resuelven por el menor precio y luego por orden de registro.
+func rankQuotations(quotations []models.Quotation) []QuotationRanking {
+	hundred := decimal.NewFromInt(100)
+
+	var minAmount, minDays decimal.Decimal
+	for i, q := range quotations {
+		if i == 0 || q.AmountVES.Decimal().LessThan(minAmount) {
+			minAmount = q.AmountVES.Decimal()
+		}
+		days := decimal.NewFromInt(int64(q.DeliveryDays))
+		if q.DeliveryDays > 0 && (minDays.IsZero() || days.LessThan(minDays)) {
+			minDays = days
+		}
+	}
+
+	ranking := make([]QuotationRanking, 0, len(quotations))
+	for _, q := range quotations {
+		row := QuotationRanking{Quotation: q}
+
+		row.PriceScore = hundred
+		if amount := q.AmountVES.Decimal(); amount.IsPositive() {
+			row.PriceScore = minAmount.Mul(hundred).Div(amount).Round(2)
+		}
+		if q.DeliveryDays > 0 {
+			row.DeliveryScore = minDays.Mul(hundred).Div(decimal.NewFromInt(int64(q.DeliveryDays))).Round(2)
+		}
+		row.QualityScore = decimal.NewFromInt(int64(q.QualityScore)).Mul(hundred).Div(decimal.NewFromInt(MaxQualityScore)).Round(2)
+
+		row.TotalScore = row.PriceScore.Mul(decimal.NewFromInt(quotationWeights.Price)).
+			Add(row.DeliveryScore.Mul(decimal.NewFromInt(quotationWeights.Delivery))).
+			Add(row.QualityScore.Mul(decimal.NewFromInt(quotationWeights.Quality))).
+			Div(hundred).Round(2)
+		ranking = append(ranking, row)
+	}
+
+	sort.SliceStable(ranking, func(i, j int) bool {
+		a, b := ranking[i], ranking[j]
+		if !a.TotalScore.Equal(b.TotalScore) {
+			return a.TotalScore.GreaterThan(b.TotalScore)
+		}
+		if c := a.Quotation.AmountVES.Cmp(b.Quotation.AmountVES); c != 0 {
+			return c < 0
+		}
+		return a.Quotation.ID < b.Quotation.ID
+	})
+	for i := range ranking {
+		ranking[i].Rank = i + 1
+	}
+	return ranking
+}
@@ -1,42 +1,114 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
+
 	"github.com/toor/backend/internal/models"
 	"github.com/toor/backend/internal/repository"
+	"gorm.io/gorm"
 )
 
 type OrderService interface {
-	CreateOrder(order *models.Order) (*models.Order, error)
-	GetAllOrders() ([]models.Order, error)
-	GetOrderById(id uint) (*models.Order, error)
+	CreateOrder(ctx context.Context, actor *Actor, order *models.Order) (*models.Order, error)
+	GetAllOrders(ctx context.Context, filter repository.OrderFilter) ([]models.Order, int64, error)
+	GetOrderById(ctx context.Context, id uint) (*models.Order, error)
+	UpdateOrder(ctx context.Context, actor *Actor, id uint, input *models.Order) (*models.Order, error)
+	CancelOrder(ctx context.Context, actor *Actor, id uint, reason string) (*models.Order, error)
+
+	// Ítems
+	GetOrderItems(ctx context.Context, orderID uint) ([]models.OrderItem, error)
+	AddOrderItem(ctx context.Context, actor *Actor, orderID uint, item *models.OrderItem) (*models.OrderItem, error)
+	UpdateOrderItem(ctx context.Context, actor *Actor, orderID, itemID uint, req *models.OrderItem) (*models.OrderItem, error)
+	DeleteOrderItem(ctx context.Context, actor *Actor, orderID, itemID uint) error
+
+	// Flujo de estados
+	TransitionOrder(ctx context.Context, actor *Actor, id uint, toStatus, reason string) (*models.Order, error)
+	GetOrderStatusHistory(ctx context.Context, id uint) ([]models.OrderStatusHistory, error)
+
+	// Cotizaciones y cuadro comparativo
+	GetOrderQuotations(ctx context.Context, orderID uint) ([]models.Quotation, error)
+	AddOrderQuotation(ctx context.Context, actor *Actor, orderID uint, quotation *models.Quotation) (*models.Quotation, error)
+	UpdateOrderQuotation(ctx context.Context, actor *Actor, orderID, quotationID uint, req *models.Quotation) (*models.Quotation, error)
+	ScoreOrderQuotation(ctx context.Context, actor *Actor, orderID, quotationID uint, deliveryDays, qualityScore int) (*models.Quotation, error)
+	DeleteOrderQuotation(ctx context.Context, actor *Actor, orderID, quotationID uint) error
+	CompareOrderQuotations(ctx context.Context, orderID uint) (*QuotationComparison, error)
+	SelectOrderQuotation(ctx context.Context, actor *Actor, orderID, quotationID uint) (*models.Order, error)
 }
 
 type orderService struct {
-	repo           repository.OrderRepository
-	counterService CounterService
+	repo             repository.OrderRepository
+	itemRepo         repository.OrderItemRepository
+	quotationRepo    repository.QuotationRepository
+	taxRateRepo      repository.TaxRateRepository
+	exchangeRateRepo repository.ExchangeRateRepository
+	procurementRepo  repository.ProcurementRepository
+	providerRepo     repository.ProviderRepository
+	masterDataRepo   repository.MasterDataRepository
+	counterService   CounterService
+	uow              repository.UnitOfWork
 }
 
-func NewOrderService(repo repository.OrderRepository, counterService CounterService) OrderService {
+func NewOrderService(
+	repo repository.OrderRepository,
+	itemRepo repository.OrderItemRepository,
+	quotationRepo repository.QuotationRepository,
+	taxRateRepo repository.TaxRateRepository,
+	exchangeRateRepo repository.ExchangeRateRepository,
+	procurementRepo repository.ProcurementRepository,
+	providerRepo repository.ProviderRepository,
+	masterDataRepo repository.MasterDataRepository,
+	counterService CounterService,
+	uow repository.UnitOfWork,
+) OrderService {
 	return &orderService{
-		repo:           repo,
-		counterService: counterService,
+		repo:             repo,
+		itemRepo:         itemRepo,
+		quotationRepo:    quotationRepo,
+		taxRateRepo:      taxRateRepo,
+		exchangeRateRepo: exchangeRateRepo,
+		procurementRepo:  procurementRepo,
+		providerRepo:     providerRepo,
+		masterDataRepo:   masterDataRepo,
+		counterService:   counterService,
+		uow:              uow,
 	}
 }
 
-func (s *orderService) CreateOrder(order *models.Order) (*models.Order, error) {
-	// --- LÓGICA DE NEGOCIO PARA GENERAR CORRELATIVO ---
-	newMemoNumber, err := s.counterService.GenerateNextID("MEMO")
-	if err != nil {
-		// Aquí es donde se usa `fmt.Errorf`, causando el error
-		return nil, fmt.Errorf("could not generate memo number: %w", err)
+func (s *orderService) CreateOrder(ctx context.Context, actor *Actor, input *models.Order) (*models.Order, error) {
+	if err := authorize(actor, models.PermOrdersWrite); err != nil {
+		return nil, err
+	}
+	// Del cliente solo se toman los datos editables de los pasos 1 a 3 y los
+	// ítems. El ID, las fechas, los números de documento, los montos y la
+	// modalidad los asigna el sistema, y toda orden nace como borrador.
+	order := &models.Order{Status: models.OrderStatusDraft}
+	applyEditableFields(order, input)
+	if err := s.resolveReferences(ctx, order); err != nil {
+		return nil, err
 	}
-	order.MemoNumber = newMemoNumber
-	// --------------------------------------------------
 
 	// --- LÓGICA DE NEGOCIO EXISTENTE ---
-	order.IvaAmount = order.BaseAmount * 0.16
-	order.TotalAmount = order.BaseAmount + order.IvaAmount
+	// Los montos se derivan de los ítems; se ignora lo que envíe el cliente.
+	for _, in := range input.Items {
+		item := models.OrderItem{
+			Description:   in.Description,
+			Quantity:      in.Quantity,
+			UnitOfMeasure: in.UnitOfMeasure,
+			UnitPrice:     in.UnitPrice,
+			TaxCode:       in.TaxCode,
+			TaxExempt:     in.TaxExempt,
+		}
+		if err := validateItem(&item); err != nil {
+			return nil, err
+		}
+		order.Items = append(order.Items, item)
+	}
+	if err := s.calculateTotals(ctx, order); err != nil {
+		return nil, err
+	}
 
 	if order.Subject == "" {
 		order.Subject = order.Concept
@@ -46,13 +118,256 @@ func (s *orderService) CreateOrder(order *models.Order) (*models.Order, error) {
 	}
 	// ------------------------------------
 
-	return s.repo.CreateOrder(order)
+	// El correlativo se consume en la misma transacción que inserta la orden:
+	// si la inserción falla, el contador vuelve atrás y no queda un hueco.
+	ctx = actor.Context(ctx)
+	err := s.uow.Do(ctx, func(tx repository.Repositories) error {
+		issued, err := s.counterService.IssueNumber(ctx, tx.Counters(), models.DocumentTypeMemo, order.UnitID)
+		if err != nil {
+			return fmt.Errorf("no se pudo generar el número de memo: %w", err)
+		}
+		order.MemoNumber = issued.Number
+		if _, err := tx.Orders().CreateOrder(ctx, order); err != nil {
+			return err
+		}
+		issued.OrderID = &order.ID
+		issued.IssuedBy = actor.Username
+		return tx.Counters().SaveIssuedNumber(ctx, issued)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *orderService) GetAllOrders(ctx context.Context, filter repository.OrderFilter) ([]models.Order, int64, error) {
+	return s.repo.GetAllOrders(ctx, filter)
+}
+
+func (s *orderService) GetOrderById(ctx context.Context, id uint) (*models.Order, error) {
+	return s.repo.GetOrderById(ctx, id)
+}
+
+// UpdateOrder aplica a la orden los datos editables de los pasos 1 a 3
+// (requisición, cotización y punto de cuenta). Los montos siguen derivándose
+// de los ítems y el estado solo cambia mediante transiciones.
+func (s *orderService) UpdateOrder(ctx context.Context, actor *Actor, id uint, input *models.Order) (*models.Order, error) {
+	if err := authorize(actor, models.PermOrdersWrite); err != nil {
+		return nil, err
+	}
+	var updated *models.Order
+	err := s.uow.Do(actor.Context(ctx), func(tx repository.Repositories) error {
+		var err error
+		updated, err = s.updateOrder(actor.Context(ctx), tx.Orders(), id, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// updateOrder aplica los cambios con el repositorio de la transacción en curso.
+func (s *orderService) updateOrder(ctx context.Context, orders repository.OrderRepository, id uint, input *models.Order) (*models.Order, error) {
+	order, err := orders.GetOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureEditable(order); err != nil {
+		return nil, err
+	}
+	applyEditableFields(order, input)
+
+	if err := s.resolveReferences(ctx, order); err != nil {
+		return nil, err
+	}
+	// La fecha del presupuesto o la exención pueden cambiar la alícuota aplicable.
+	if err := s.calculateTotals(ctx, order); err != nil {
+		return nil, err
+	}
+
+	if err := orders.UpdateOrder(ctx, order); err != nil {
+		return nil, err
+	}
+	if err := orders.UpdateTotals(ctx, order); err != nil {
+		return nil, err
+	}
+	return orders.GetOrderById(ctx, id)
+}
+
+// applyEditableFields copia de input a order los datos que el usuario puede
+// editar en los pasos 1 a 3 (requisición, cotización y punto de cuenta).
+func applyEditableFields(order, input *models.Order) {
+	// Paso 1: Requisición
+	order.MemoDate = input.MemoDate
+	order.UnitID = input.UnitID
+	order.OfficialID = input.OfficialID
+	order.RequestingUnit = input.RequestingUnit
+	order.ResponsibleOfficial = input.ResponsibleOfficial
+	order.Concept = input.Concept
+	// Paso 2: Cotización
+	order.ProviderID = input.ProviderID
+	order.Provider = input.Provider
+	order.DocumentType = input.DocumentType
+	order.BudgetNumber = input.BudgetNumber
+	order.BudgetDate = input.BudgetDate
+	order.DeliveryTime = input.DeliveryTime
+	order.OfferQuality = input.OfferQuality
+	order.TaxExempt = input.TaxExempt
+	order.Currency = input.Currency
+	// Paso 3: Punto de Cuenta
+	order.PriceInquiryType = input.PriceInquiryType
+	order.ContractType = input.ContractType
+	order.Subject = input.Subject
+	order.Synthesis = input.Synthesis
+	order.ProgrammaticCategory = input.ProgrammaticCategory
+	order.UEL = input.UEL
 }
 
-func (s *orderService) GetAllOrders() ([]models.Order, error) {
-	return s.repo.GetAllOrders()
+// CancelOrder anula la orden. El registro y su número de memo se conservan,
+// de modo que el correlativo consumido sigue constando.
+func (s *orderService) CancelOrder(ctx context.Context, actor *Actor, id uint, reason string) (*models.Order, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrCancelReasonRequired
+	}
+	return s.TransitionOrder(ctx, actor, id, models.OrderStatusCancelled, reason)
+}
+
+// --- Ítems ---
+
+func (s *orderService) GetOrderItems(ctx context.Context, orderID uint) ([]models.OrderItem, error) {
+	if _, err := s.repo.GetOrderById(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.itemRepo.GetByOrder(ctx, orderID)
+}
+
+func (s *orderService) AddOrderItem(ctx context.Context, actor *Actor, orderID uint, item *models.OrderItem) (*models.OrderItem, error) {
+	if err := authorize(actor, models.PermOrdersWrite); err != nil {
+		return nil, err
+	}
+	if err := validateItem(item); err != nil {
+		return nil, err
+	}
+	item.ID = 0
+	item.OrderID = orderID
+	// El ítem y los nuevos totales de la orden se guardan juntos.
+	ctx = actor.Context(ctx)
+	err := s.uow.Do(ctx, func(tx repository.Repositories) error {
+		if _, err := editableOrder(ctx, tx.Orders(), orderID); err != nil {
+			return err
+		}
+		if err := tx.Items().Create(ctx, item); err != nil {
+			return err
+		}
+		return s.recalculateOrder(ctx, tx.Orders(), orderID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Se devuelve con la alícuota y el total calculados.
+	return s.itemRepo.GetByID(ctx, orderID, item.ID)
+}
+
+func (s *orderService) UpdateOrderItem(ctx context.Context, actor *Actor, orderID, itemID uint, req *models.OrderItem) (*models.OrderItem, error) {
+	if err := authorize(actor, models.PermOrdersWrite); err != nil {
+		return nil, err
+	}
+	ctx = actor.Context(ctx)
+	err := s.uow.Do(ctx, func(tx repository.Repositories) error {
+		if _, err := editableOrder(ctx, tx.Orders(), orderID); err != nil {
+			return err
+		}
+		item, err := tx.Items().GetByID(ctx, orderID, itemID)
+		if err != nil {
+			return err
+		}
+		item.Description = req.Description
+		item.Quantity = req.Quantity
+		item.UnitOfMeasure = req.UnitOfMeasure
+		item.UnitPrice = req.UnitPrice
+		item.TaxCode = req.TaxCode
+		item.TaxExempt = req.TaxExempt
+		if err := validateItem(item); err != nil {
+			return err
+		}
+		if err := tx.Items().Update(ctx, item); err != nil {
+			return err
+		}
+		return s.recalculateOrder(ctx, tx.Orders(), orderID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.itemRepo.GetByID(ctx, orderID, itemID)
 }
 
-func (s *orderService) GetOrderById(id uint) (*models.Order, error) {
-	return s.repo.GetOrderById(id)
-}
\ No newline at end of file
+func (s *orderService) DeleteOrderItem(ctx context.Context, actor *Actor, orderID, itemID uint) error {
+	if err := authorize(actor, models.PermOrdersWrite); err != nil {
+		return err
+	}
+	ctx = actor.Context(ctx)
+	return s.uow.Do(ctx, func(tx repository.Repositories) error {
+		if _, err := editableOrder(ctx, tx.Orders(), orderID); err != nil {
+			return err
+		}
+		if err := tx.Items().Delete(ctx, orderID, itemID); err != nil {
+			return err
+		}
+		return s.recalculateOrder(ctx, tx.Orders(), orderID)
+	})
+}
+
+// editableOrder lee la orden con el repositorio de la transacción en curso y
+// verifica que todavía admita cambios en sus ítems y cotizaciones.
+func editableOrder(ctx context.Context, orders repository.OrderRepository, orderID uint) (*models.Order, error) {
+	order, err := orders.GetOrderById(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureEditable(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// resolveReferences verifica que la unidad, el funcionario y el proveedor
+// indicados existan y estén activos, y guarda sus nombres en la orden.
+func (s *orderService) resolveReferences(ctx context.Context, order *models.Order) error {
+	order.Unit, order.Official, order.ProviderRef = nil, nil, nil
+
+	if order.UnitID != nil {
+		unit, err := s.masterDataRepo.GetUnitByID(ctx, *order.UnitID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !unit.IsActive) {
+			return fmt.Errorf("%w: la unidad %d no existe o está inactiva", ErrInvalidReference, *order.UnitID)
+		}
+		if err != nil {
+			return err
+		}
+		order.RequestingUnit = unit.Name
+	}
+
+	if order.OfficialID != nil {
+		official, err := s.masterDataRepo.GetOfficialByID(ctx, *order.OfficialID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !official.IsActive) {
+			return fmt.Errorf("%w: el funcionario %d no existe o está inactivo", ErrInvalidReference, *order.OfficialID)
+		}
+		if err != nil {
+			return err
+		}
+		order.ResponsibleOfficial = official.FullName
+	}
+
+	if order.ProviderID != nil {
+		provider, err := s.providerRepo.GetByID(ctx, *order.ProviderID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !provider.IsActive) {
+			return fmt.Errorf("%w: el proveedor %d no existe o está inactivo", ErrInvalidReference, *order.ProviderID)
+		}
+		if err != nil {
+			return err
+		}
+		order.Provider = provider.Name
+	}
+
+	return nil
+}
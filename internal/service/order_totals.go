@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/money"
+	"github.com/toor/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// recalculateOrder vuelve a calcular la orden con sus ítems vigentes y guarda los montos.
+func (s *orderService) recalculateOrder(ctx context.Context, orders repository.OrderRepository, orderID uint) error {
+	order, err := orders.GetOrderById(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if err := s.calculateTotals(ctx, order); err != nil {
+		return err
+	}
+	return orders.UpdateTotals(ctx, order)
+}
+
+// calculateTotals asigna a cada ítem la alícuota vigente en la fecha de la
+// orden (o 0 si la orden o el ítem están exentos), calcula el total de cada
+// línea y, a partir de ellas, la base imponible, el IVA y el total, además
+// de su equivalente en bolívares y la modalidad de contratación que corresponde.
+// Las reglas de redondeo están descritas en el paquete money.
+func (s *orderService) calculateTotals(ctx context.Context, order *models.Order) error {
+	resolver := newTaxRateResolver(s.taxRateRepo, taxDate(order))
+
+	order.AppliedTaxRate = decimal.Zero
+	if !order.TaxExempt {
+		rate, err := resolver.rate(ctx, models.TaxCodeGeneral)
+		if err != nil {
+			return err
+		}
+		order.AppliedTaxRate = rate
+	}
+
+	// Base imponible agrupada por porcentaje, para calcular el IVA una vez por alícuota.
+	type taxGroup struct {
+		rate decimal.Decimal
+		base money.Money
+	}
+	var groups []*taxGroup
+	groupFor := func(rate decimal.Decimal) *taxGroup {
+		for _, g := range groups {
+			if g.rate.Equal(rate) {
+				return g
+			}
+		}
+		g := &taxGroup{rate: rate}
+		groups = append(groups, g)
+		return g
+	}
+
+	base := money.Zero
+	for i := range order.Items {
+		item := &order.Items[i]
+		if item.TaxCode == "" {
+			item.TaxCode = models.TaxCodeGeneral
+		}
+		item.TaxRate = decimal.Zero
+		if !order.TaxExempt && !item.TaxExempt {
+			rate, err := resolver.rate(ctx, item.TaxCode)
+			if err != nil {
+				return err
+			}
+			item.TaxRate = rate
+		}
+		item.LineTotal = item.UnitPrice.Mul(item.Quantity)
+		base = base.Add(item.LineTotal)
+
+		group := groupFor(item.TaxRate)
+		group.base = group.base.Add(item.LineTotal)
+	}
+
+	iva := money.Zero
+	for _, g := range groups {
+		iva = iva.Add(g.base.Percent(g.rate))
+	}
+
+	order.BaseAmount = base
+	order.IvaAmount = iva
+	order.TotalAmount = base.Add(iva)
+	if err := s.convertToVES(ctx, order); err != nil {
+		return err
+	}
+	return s.determineModality(ctx, order)
+}
+
+// convertToVES registra en la orden la tasa vigente en la fecha de la orden y
+// el equivalente en bolívares de sus montos. Las órdenes en bolívares usan tasa 1.
+func (s *orderService) convertToVES(ctx context.Context, order *models.Order) error {
+	if order.Currency == "" {
+		order.Currency = models.CurrencyVES
+	}
+	if order.Currency == models.CurrencyVES {
+		order.ExchangeRateID = nil
+		order.ExchangeRate = decimal.NewFromInt(1)
+		order.ExchangeRateDate = nil
+		order.BaseAmountVES = order.BaseAmount
+		order.IvaAmountVES = order.IvaAmount
+		order.TotalAmountVES = order.TotalAmount
+		return nil
+	}
+
+	rate, err := s.findExchangeRate(ctx, order.Currency, taxDate(order))
+	if err != nil {
+		return err
+	}
+	rateDate := rate.Date
+	order.ExchangeRateID = &rate.ID
+	order.ExchangeRate = rate.Rate
+	order.ExchangeRateDate = &rateDate
+	// Se convierten la base y el IVA por separado para que el total en
+	// bolívares sea la suma de lo que se imprime en el punto de cuenta.
+	order.BaseAmountVES = order.BaseAmount.Mul(rate.Rate)
+	order.IvaAmountVES = order.IvaAmount.Mul(rate.Rate)
+	order.TotalAmountVES = order.BaseAmountVES.Add(order.IvaAmountVES)
+	return nil
+}
+
+// findExchangeRate busca la tasa más reciente de una moneda extranjera con
+// fecha igual o anterior a date.
+func (s *orderService) findExchangeRate(ctx context.Context, currency string, date time.Time) (*models.ExchangeRate, error) {
+	if !foreignCurrencies[currency] {
+		return nil, fmt.Errorf("%w: moneda %q no admitida", ErrInvalidReference, currency)
+	}
+	rate, err := s.exchangeRateRepo.FindEffective(ctx, currency, date)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %s al %s", ErrExchangeRateNotFound, currency, date.Format("02/01/2006"))
+	}
+	return rate, err
+}
+
+// validateItem verifica que la cantidad sea positiva y el precio no negativo.
+func validateItem(item *models.OrderItem) error {
+	if !item.Quantity.IsPositive() {
+		return fmt.Errorf("%w: la cantidad de %q debe ser mayor que cero", ErrInvalidItem, item.Description)
+	}
+	if item.UnitPrice.IsNegative() {
+		return fmt.Errorf("%w: el precio unitario de %q no puede ser negativo", ErrInvalidItem, item.Description)
+	}
+	return nil
+}
+
+// taxDate es la fecha con la que se busca la alícuota: la del presupuesto,
+// o la del memo si aún no hay presupuesto.
+func taxDate(order *models.Order) time.Time {
+	if !order.BudgetDate.IsZero() {
+		return order.BudgetDate
+	}
+	if !order.MemoDate.IsZero() {
+		return order.MemoDate
+	}
+	return time.Now()
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/repository"
+)
+
+// ErrInvalidTransition se devuelve cuando se intenta mover una orden a un
+// estado que no es alcanzable desde su estado actual.
+var ErrInvalidTransition = apperror.Conflict("INVALID_TRANSITION", "transición de estado no permitida")
+
+// ErrOrderLocked se devuelve al intentar editar una orden que ya fue aprobada
+// (o que está en un estado posterior, incluida la anulación).
+var ErrOrderLocked = apperror.Conflict("ORDER_LOCKED", "la orden ya no puede modificarse en su estado actual")
+
+// ErrCancelReasonRequired se devuelve cuando se intenta anular sin indicar el motivo.
+var ErrCancelReasonRequired = apperror.Validation("CANCEL_REASON_REQUIRED", "debe indicar el motivo de la anulación")
+
+// lockedStatuses son los estados a partir de los cuales la orden deja de ser editable.
+var lockedStatuses = map[string]bool{
+	models.OrderStatusApproved:  true,
+	models.OrderStatusIssued:    true,
+	models.OrderStatusReceived:  true,
+	models.OrderStatusPaid:      true,
+	models.OrderStatusCancelled: true,
+}
+
+// orderTransitions define el ciclo de vida de una orden:
+// Borrador → Requisición → Cotización → Punto de Cuenta → Aprobada →
+// Orden Emitida → Recibida → Pagada. Mientras no se haya pagado, la orden
+// puede anularse. Pagada y Anulada son estados finales.
+var orderTransitions = map[string][]string{
+	models.OrderStatusDraft:        {models.OrderStatusRequisition, models.OrderStatusCancelled},
+	models.OrderStatusRequisition:  {models.OrderStatusQuotation, models.OrderStatusDraft, models.OrderStatusCancelled},
+	models.OrderStatusQuotation:    {models.OrderStatusAccountPoint, models.OrderStatusRequisition, models.OrderStatusCancelled},
+	models.OrderStatusAccountPoint: {models.OrderStatusApproved, models.OrderStatusQuotation, models.OrderStatusCancelled},
+	models.OrderStatusApproved:     {models.OrderStatusIssued, models.OrderStatusCancelled},
+	models.OrderStatusIssued:       {models.OrderStatusReceived, models.OrderStatusCancelled},
+	models.OrderStatusReceived:     {models.OrderStatusPaid, models.OrderStatusCancelled},
+	models.OrderStatusPaid:         {},
+	models.OrderStatusCancelled:    {},
+}
+
+// normalizeStatus trata el estado heredado "En Proceso" como un borrador.
+func normalizeStatus(status string) string {
+	if status == "" || status == models.OrderStatusLegacy {
+		return models.OrderStatusDraft
+	}
+	return status
+}
+
+// CanTransition indica si una orden en el estado from puede pasar al estado to.
+func CanTransition(from, to string) bool {
+	for _, next := range orderTransitions[normalizeStatus(from)] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureEditable devuelve ErrOrderLocked si la orden ya no admite cambios.
+func ensureEditable(order *models.Order) error {
+	if lockedStatuses[order.Status] {
+		return fmt.Errorf("%w (%s)", ErrOrderLocked, order.Status)
+	}
+	return nil
+}
+
+// authorizeTransition verifica que el actor pueda mover la orden al estado
+// indicado. Aprobar exige orders:approve y que la orden sea de la unidad del
+// usuario (salvo orders:approve-all); quien puede aprobar también puede
+// devolver o anular una orden en punto de cuenta. El resto de las
+// transiciones exige orders:write.
+func authorizeTransition(actor *Actor, order *models.Order, toStatus string) error {
+	approving := toStatus == models.OrderStatusApproved
+	if !approving && actor.Can(models.PermOrdersWrite) {
+		return nil
+	}
+	if !approving && order.Status != models.OrderStatusAccountPoint {
+		return authorize(actor, models.PermOrdersWrite)
+	}
+	if actor.Can(models.PermOrdersApproveAll) {
+		return nil
+	}
+	if err := authorize(actor, models.PermOrdersApprove); err != nil {
+		return err
+	}
+	if order.UnitID == nil || actor.UnitID == nil || *order.UnitID != *actor.UnitID {
+		return fmt.Errorf("%w: solo puede decidir sobre órdenes de su unidad", ErrForbidden)
+	}
+	return nil
+}
+
+func (s *orderService) TransitionOrder(ctx context.Context, actor *Actor, id uint, toStatus, reason string) (*models.Order, error) {
+	order, err := s.repo.GetOrderById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, known := orderTransitions[toStatus]; !known {
+		return nil, fmt.Errorf("%w: estado desconocido %q", ErrInvalidTransition, toStatus)
+	}
+	if !CanTransition(order.Status, toStatus) {
+		return nil, fmt.Errorf("%w: de %q a %q", ErrInvalidTransition, order.Status, toStatus)
+	}
+	if err := authorizeTransition(actor, order, toStatus); err != nil {
+		return nil, err
+	}
+
+	entry := &models.OrderStatusHistory{
+		OrderID:    order.ID,
+		FromStatus: order.Status,
+		ToStatus:   toStatus,
+		ChangedBy:  actor.Username,
+		Reason:     reason,
+	}
+	// El número del documento del paso se consume en la misma transacción que
+	// el cambio de estado, de modo que una transición rechazada no deja huecos.
+	err = s.uow.Do(actor.Context(ctx), func(tx repository.Repositories) error {
+		if err := s.ensureModality(actor.Context(ctx), tx.Orders(), order, toStatus); err != nil {
+			return err
+		}
+		issued, err := s.assignDocumentNumber(actor.Context(ctx), tx.Counters(), order, toStatus)
+		if err != nil {
+			return err
+		}
+		if err := tx.Orders().UpdateStatus(actor.Context(ctx), order, entry); err != nil {
+			return err
+		}
+		if issued == nil {
+			return nil
+		}
+		issued.OrderID = &order.ID
+		issued.IssuedBy = actor.Username
+		return tx.Counters().SaveIssuedNumber(actor.Context(ctx), issued)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// assignDocumentNumber numera el documento que corresponde al paso: el punto
+// de cuenta al someterlo a aprobación y la orden de compra al emitirla. Si la
+// orden vuelve atrás y avanza de nuevo conserva el número ya asignado, y no se
+// devuelve ningún número nuevo.
+func (s *orderService) assignDocumentNumber(ctx context.Context, counters repository.CounterRepository, order *models.Order, toStatus string) (*models.IssuedNumber, error) {
+	var docType string
+	var number *string
+	switch toStatus {
+	case models.OrderStatusAccountPoint:
+		docType, number = models.DocumentTypeAccountPoint, &order.AccountPointNumber
+	case models.OrderStatusIssued:
+		docType, number = models.DocumentTypeOrder, &order.PurchaseOrderNumber
+	default:
+		return nil, nil
+	}
+	if *number != "" {
+		return nil, nil
+	}
+	issued, err := s.counterService.IssueNumber(ctx, counters, docType, order.UnitID)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo generar el número de %s: %w", docType, err)
+	}
+	*number = issued.Number
+	return issued, nil
+}
+
+func (s *orderService) GetOrderStatusHistory(ctx context.Context, id uint) ([]models.OrderStatusHistory, error) {
+	if _, err := s.repo.GetOrderById(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetStatusHistory(ctx, id)
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/money"
+	"github.com/toor/backend/internal/repository"
+)
+
+var (
+	// ErrInvalidProcurementConfig indica un valor de UT o un umbral con datos inválidos.
+	ErrInvalidProcurementConfig = apperror.Validation("INVALID_PROCUREMENT_CONFIG", "configuración de contrataciones inválida")
+	// ErrProcurementNotConfigured indica que falta el valor de la UT o los umbrales para la orden.
+	ErrProcurementNotConfigured = apperror.Unprocessable("PROCUREMENT_NOT_CONFIGURED", "no hay unidad tributaria o umbrales configurados para determinar la modalidad")
+	// ErrModalityMismatch indica que la modalidad elegida no corresponde al monto de la orden.
+	ErrModalityMismatch = apperror.Unprocessable("MODALITY_MISMATCH", "la modalidad de contratación no corresponde al monto de la orden")
+	// ErrInvalidContractType indica un tipo de contratación desconocido.
+	ErrInvalidContractType = apperror.Validation("INVALID_CONTRACT_TYPE", "tipo de contratación inválido")
+)
+
+var contractTypes = map[string]bool{
+	models.ContractTypeGoods:    true,
+	models.ContractTypeServices: true,
+	models.ContractTypeWorks:    true,
+}
+
+// modalities son las modalidades que pueden resultar de un umbral.
+var modalities = map[string]bool{
+	models.ModalityPriceInquiry: true,
+	models.ModalityClosedTender: true,
+	models.ModalityOpenTender:   true,
+}
+
+type ProcurementService interface {
+	// Unidad tributaria
+	CreateTaxUnit(ctx context.Context, actor *Actor, value *models.TaxUnitValue) (*models.TaxUnitValue, error)
+	GetAllTaxUnits(ctx context.Context) ([]models.TaxUnitValue, error)
+	UpdateTaxUnit(ctx context.Context, actor *Actor, id uint, req *models.TaxUnitValue) (*models.TaxUnitValue, error)
+	DeleteTaxUnit(ctx context.Context, actor *Actor, id uint) error
+	// Umbrales por modalidad
+	CreateThreshold(ctx context.Context, actor *Actor, threshold *models.ProcurementThreshold) (*models.ProcurementThreshold, error)
+	GetAllThresholds(ctx context.Context) ([]models.ProcurementThreshold, error)
+	UpdateThreshold(ctx context.Context, actor *Actor, id uint, req *models.ProcurementThreshold) (*models.ProcurementThreshold, error)
+	DeleteThreshold(ctx context.Context, actor *Actor, id uint) error
+	// SeedDefaults registra los umbrales de la Ley de Contrataciones Públicas si la tabla está vacía.
+	SeedDefaults(ctx context.Context) error
+}
+
+type procurementService struct {
+	repo repository.ProcurementRepository
+}
+
+func NewProcurementService(repo repository.ProcurementRepository) ProcurementService {
+	return &procurementService{repo: repo}
+}
+
+// Unidad tributaria
+func (s *procurementService) CreateTaxUnit(ctx context.Context, actor *Actor, value *models.TaxUnitValue) (*models.TaxUnitValue, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	value.ID = 0
+	if err := validateTaxUnit(value); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateTaxUnit(ctx, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (s *procurementService) GetAllTaxUnits(ctx context.Context) ([]models.TaxUnitValue, error) {
+	return s.repo.GetAllTaxUnits(ctx)
+}
+
+func (s *procurementService) UpdateTaxUnit(ctx context.Context, actor *Actor, id uint, req *models.TaxUnitValue) (*models.TaxUnitValue, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	value, err := s.repo.GetTaxUnitByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	value.ValidFrom = req.ValidFrom
+	value.Value = req.Value
+	value.Gazette = req.Gazette
+	if err := validateTaxUnit(value); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateTaxUnit(ctx, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (s *procurementService) DeleteTaxUnit(ctx context.Context, actor *Actor, id uint) error {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return err
+	}
+	return s.repo.DeleteTaxUnit(ctx, id)
+}
+
+// Umbrales por modalidad
+func (s *procurementService) CreateThreshold(ctx context.Context, actor *Actor, threshold *models.ProcurementThreshold) (*models.ProcurementThreshold, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	threshold.ID = 0
+	if err := validateThreshold(threshold); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateThreshold(ctx, threshold); err != nil {
+		return nil, err
+	}
+	return threshold, nil
+}
+
+func (s *procurementService) GetAllThresholds(ctx context.Context) ([]models.ProcurementThreshold, error) {
+	return s.repo.GetAllThresholds(ctx)
+}
+
+func (s *procurementService) UpdateThreshold(ctx context.Context, actor *Actor, id uint, req *models.ProcurementThreshold) (*models.ProcurementThreshold, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	threshold, err := s.repo.GetThresholdByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	threshold.ContractType = req.ContractType
+	threshold.Modality = req.Modality
+	threshold.MaxUT = req.MaxUT
+	if err := validateThreshold(threshold); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateThreshold(ctx, threshold); err != nil {
+		return nil, err
+	}
+	return threshold, nil
+}
+
+func (s *procurementService) DeleteThreshold(ctx context.Context, actor *Actor, id uint) error {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return err
+	}
+	return s.repo.DeleteThreshold(ctx, id)
+}
+
+func (s *procurementService) SeedDefaults(ctx context.Context) error {
+	count, err := s.repo.CountThresholds(ctx)
+	if err != nil || count > 0 {
+		return err
+	}
+	ut := func(v int64) *decimal.Decimal {
+		d := decimal.NewFromInt(v)
+		return &d
+	}
+	// Límites de la Ley de Contrataciones Públicas (2014), en UT.
+	defaults := []models.ProcurementThreshold{
+		{ContractType: models.ContractTypeGoods, Modality: models.ModalityPriceInquiry, MaxUT: ut(5000)},
+		{ContractType: models.ContractTypeGoods, Modality: models.ModalityClosedTender, MaxUT: ut(20000)},
+		{ContractType: models.ContractTypeGoods, Modality: models.ModalityOpenTender},
+		{ContractType: models.ContractTypeServices, Modality: models.ModalityPriceInquiry, MaxUT: ut(10000)},
+		{ContractType: models.ContractTypeServices, Modality: models.ModalityClosedTender, MaxUT: ut(30000)},
+		{ContractType: models.ContractTypeServices, Modality: models.ModalityOpenTender},
+		{ContractType: models.ContractTypeWorks, Modality: models.ModalityPriceInquiry, MaxUT: ut(20000)},
+		{ContractType: models.ContractTypeWorks, Modality: models.ModalityClosedTender, MaxUT: ut(50000)},
+		{ContractType: models.ContractTypeWorks, Modality: models.ModalityOpenTender},
+	}
+	for i := range defaults {
+		if err := s.repo.CreateThreshold(ctx, &defaults[i]); err != nil {
+			return err
+		}
+	}
+	log.Println("Default procurement thresholds created")
+	return nil
+}
+
+func validateTaxUnit(value *models.TaxUnitValue) error {
+	if value.ValidFrom.IsZero() {
+		return fmt.Errorf("%w: la fecha de vigencia es obligatoria", ErrInvalidProcurementConfig)
+	}
+	if !value.Value.GreaterThan(money.Zero) {
+		return fmt.Errorf("%w: el valor de la UT debe ser mayor que cero", ErrInvalidProcurementConfig)
+	}
+	return nil
+}
+
+func validateThreshold(threshold *models.ProcurementThreshold) error {
+	if !contractTypes[threshold.ContractType] {
+		return fmt.Errorf("%w: %q", ErrInvalidContractType, threshold.ContractType)
+	}
+	if !modalities[threshold.Modality] {
+		return fmt.Errorf("%w: modalidad %q no admitida", ErrInvalidProcurementConfig, threshold.Modality)
+	}
+	if threshold.MaxUT != nil && !threshold.MaxUT.IsPositive() {
+		return fmt.Errorf("%w: el límite en UT debe ser mayor que cero", ErrInvalidProcurementConfig)
+	}
+	return nil
+}
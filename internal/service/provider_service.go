@@ -1,16 +1,32 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/toor/backend/internal/apperror"
 	"github.com/toor/backend/internal/models"
 	"github.com/toor/backend/internal/repository"
+	"github.com/toor/backend/internal/rif"
 )
 
+// ErrInvalidRIF indica que el RIF del proveedor no es válido.
+var ErrInvalidRIF = apperror.Validation("INVALID_RIF", "RIF inválido")
+
 type ProviderService interface {
-	CreateProvider(provider *models.Provider) (*models.Provider, error)
-	GetAllProviders() ([]models.Provider, error)
-	GetProviderByID(id uint) (*models.Provider, error)
-	UpdateProvider(provider *models.Provider) (*models.Provider, error)
-	DeleteProvider(id uint) error
+	CreateProvider(ctx context.Context, actor *Actor, provider *models.Provider) (*models.Provider, error)
+	GetAllProviders(ctx context.Context) ([]models.Provider, error)
+	GetProviderByID(ctx context.Context, id uint) (*models.Provider, error)
+	UpdateProvider(ctx context.Context, actor *Actor, provider *models.Provider) (*models.Provider, error)
+	DeleteProvider(ctx context.Context, actor *Actor, id uint) error
+	// RIFReport revisa los RIF de los proveedores existentes.
+	RIFReport(ctx context.Context) (*RIFReport, error)
+	// NormalizeRIFs guarda en la forma canónica los RIF válidos que no lo
+	// están, salvo los de proveedores duplicados, y devuelve el informe con
+	// las correcciones aplicadas.
+	NormalizeRIFs(ctx context.Context, actor *Actor) (*RIFReport, error)
 }
 
 type providerService struct {
@@ -21,28 +37,177 @@ func NewProviderService(repo repository.ProviderRepository) ProviderService {
 	return &providerService{repo: repo}
 }
 
-func (s *providerService) CreateProvider(provider *models.Provider) (*models.Provider, error) {
-	if err := s.repo.Create(provider); err != nil {
+func (s *providerService) CreateProvider(ctx context.Context, actor *Actor, provider *models.Provider) (*models.Provider, error) {
+	if err := authorize(actor, models.PermProvidersWrite); err != nil {
+		return nil, err
+	}
+	if err := normalizeRIF(provider); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(actor.Context(ctx), provider); err != nil {
 		return nil, err
 	}
 	return provider, nil
 }
 
-func (s *providerService) GetAllProviders() ([]models.Provider, error) {
-	return s.repo.GetAll()
+func (s *providerService) GetAllProviders(ctx context.Context) ([]models.Provider, error) {
+	return s.repo.GetAll(ctx)
 }
 
-func (s *providerService) GetProviderByID(id uint) (*models.Provider, error) {
-	return s.repo.GetByID(id)
+func (s *providerService) GetProviderByID(ctx context.Context, id uint) (*models.Provider, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
-func (s *providerService) UpdateProvider(provider *models.Provider) (*models.Provider, error) {
-	if err := s.repo.Update(provider); err != nil {
+func (s *providerService) UpdateProvider(ctx context.Context, actor *Actor, provider *models.Provider) (*models.Provider, error) {
+	if err := authorize(actor, models.PermProvidersWrite); err != nil {
+		return nil, err
+	}
+	if err := normalizeRIF(provider); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(actor.Context(ctx), provider); err != nil {
 		return nil, err
 	}
 	return provider, nil
 }
 
-func (s *providerService) DeleteProvider(id uint) error {
-	return s.repo.Delete(id)
-}
\ No newline at end of file
+func (s *providerService) DeleteProvider(ctx context.Context, actor *Actor, id uint) error {
+	if err := authorize(actor, models.PermProvidersWrite); err != nil {
+		return err
+	}
+	inUse, err := s.repo.IsInUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return newInUseError("no se puede eliminar el proveedor: está asignado a una o más órdenes")
+	}
+	return s.repo.Delete(actor.Context(ctx), id)
+}
+
+// normalizeRIF deja el RIF del proveedor en la forma canónica X-NNNNNNNN-D.
+// El RIF es opcional.
+func normalizeRIF(provider *models.Provider) error {
+	provider.RIF = strings.TrimSpace(provider.RIF)
+	if provider.RIF == "" {
+		return nil
+	}
+	normalized, err := rif.Normalize(provider.RIF)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRIF, err)
+	}
+	provider.RIF = normalized
+	return nil
+}
+
+// RIFReport es el resultado de revisar los RIF de los proveedores cargados
+// antes de que se validaran.
+type RIFReport struct {
+	Checked int `json:"checked"`
+	// Invalid son los RIF que no pueden normalizarse y deben corregirse a mano.
+	Invalid []RIFIssue `json:"invalid"`
+	// ToNormalize son los RIF válidos que no están en la forma canónica.
+	ToNormalize []RIFIssue `json:"toNormalize"`
+	// Duplicates agrupa los proveedores con el mismo RIF normalizado.
+	Duplicates []RIFDuplicate `json:"duplicates"`
+}
+
+// RIFIssue es un proveedor con un RIF inválido o por normalizar.
+type RIFIssue struct {
+	ProviderID uint   `json:"providerId"`
+	Name       string `json:"name"`
+	RIF        string `json:"rif"`
+	Normalized string `json:"normalized,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// Applied indica que NormalizeRIFs guardó el RIF normalizado.
+	Applied bool `json:"applied"`
+}
+
+// RIFDuplicate son los proveedores que comparten un RIF una vez normalizado.
+type RIFDuplicate struct {
+	RIF       string     `json:"rif"`
+	Providers []RIFIssue `json:"providers"`
+}
+
+func (s *providerService) RIFReport(ctx context.Context) (*RIFReport, error) {
+	providers, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return buildRIFReport(providers), nil
+}
+
+func (s *providerService) NormalizeRIFs(ctx context.Context, actor *Actor) (*RIFReport, error) {
+	if err := authorize(actor, models.PermProvidersWrite); err != nil {
+		return nil, err
+	}
+	providers, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := buildRIFReport(providers)
+
+	// Los duplicados se resuelven a mano: al normalizarlos chocarían con el
+	// índice único del RIF.
+	duplicated := map[uint]bool{}
+	for _, d := range report.Duplicates {
+		for _, p := range d.Providers {
+			duplicated[p.ProviderID] = true
+		}
+	}
+	byID := map[uint]*models.Provider{}
+	for i := range providers {
+		byID[providers[i].ID] = &providers[i]
+	}
+	for i := range report.ToNormalize {
+		issue := &report.ToNormalize[i]
+		if duplicated[issue.ProviderID] {
+			continue
+		}
+		provider := byID[issue.ProviderID]
+		provider.RIF = issue.Normalized
+		if err := s.repo.Update(actor.Context(ctx), provider); err != nil {
+			return report, fmt.Errorf("proveedor %d: %w", provider.ID, err)
+		}
+		issue.Applied = true
+	}
+	return report, nil
+}
+
+// buildRIFReport clasifica los RIF de los proveedores. Los proveedores sin
+// RIF no se informan.
+func buildRIFReport(providers []models.Provider) *RIFReport {
+	report := &RIFReport{
+		Checked:     len(providers),
+		Invalid:     []RIFIssue{},
+		ToNormalize: []RIFIssue{},
+		Duplicates:  []RIFDuplicate{},
+	}
+	byRIF := map[string][]RIFIssue{}
+	for _, p := range providers {
+		if strings.TrimSpace(p.RIF) == "" {
+			continue
+		}
+		issue := RIFIssue{ProviderID: p.ID, Name: p.Name, RIF: p.RIF}
+		normalized, err := rif.Normalize(p.RIF)
+		if err != nil {
+			issue.Error = err.Error()
+			report.Invalid = append(report.Invalid, issue)
+			continue
+		}
+		issue.Normalized = normalized
+		if normalized != p.RIF {
+			report.ToNormalize = append(report.ToNormalize, issue)
+		}
+		byRIF[normalized] = append(byRIF[normalized], issue)
+	}
+	for normalized, issues := range byRIF {
+		if len(issues) > 1 {
+			report.Duplicates = append(report.Duplicates, RIFDuplicate{RIF: normalized, Providers: issues})
+		}
+	}
+	sort.Slice(report.Duplicates, func(i, j int) bool {
+		return report.Duplicates[i].RIF < report.Duplicates[j].RIF
+	})
+	return report
+}
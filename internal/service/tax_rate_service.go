@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTaxRateOverlap indica que ya existe una alícuota del mismo código vigente en ese rango.
+	ErrTaxRateOverlap = apperror.Conflict("TAX_RATE_OVERLAP", "ya existe una alícuota del mismo código vigente en ese rango de fechas")
+	// ErrInvalidTaxRate indica datos incoherentes (porcentaje o rango de fechas).
+	ErrInvalidTaxRate = apperror.Validation("INVALID_TAX_RATE", "alícuota inválida")
+	// ErrTaxRateNotConfigured indica que no hay alícuota vigente para calcular la orden.
+	ErrTaxRateNotConfigured = apperror.Unprocessable("TAX_RATE_NOT_CONFIGURED", "no hay alícuota de IVA configurada para la fecha")
+)
+
+type TaxRateService interface {
+	CreateTaxRate(ctx context.Context, actor *Actor, rate *models.TaxRate) (*models.TaxRate, error)
+	GetAllTaxRates(ctx context.Context) ([]models.TaxRate, error)
+	UpdateTaxRate(ctx context.Context, actor *Actor, id uint, req *models.TaxRate) (*models.TaxRate, error)
+	DeleteTaxRate(ctx context.Context, actor *Actor, id uint) error
+	// SeedDefaults registra las alícuotas vigentes si la tabla está vacía.
+	SeedDefaults(ctx context.Context) error
+}
+
+type taxRateService struct {
+	repo repository.TaxRateRepository
+}
+
+func NewTaxRateService(repo repository.TaxRateRepository) TaxRateService {
+	return &taxRateService{repo: repo}
+}
+
+func (s *taxRateService) CreateTaxRate(ctx context.Context, actor *Actor, rate *models.TaxRate) (*models.TaxRate, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	rate.ID = 0
+	if err := s.validate(ctx, rate); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *taxRateService) GetAllTaxRates(ctx context.Context) ([]models.TaxRate, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *taxRateService) UpdateTaxRate(ctx context.Context, actor *Actor, id uint, req *models.TaxRate) (*models.TaxRate, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	rate, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rate.Code = req.Code
+	rate.Name = req.Name
+	rate.Rate = req.Rate
+	rate.ValidFrom = req.ValidFrom
+	rate.ValidTo = req.ValidTo
+	if err := s.validate(ctx, rate); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *taxRateService) DeleteTaxRate(ctx context.Context, actor *Actor, id uint) error {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *taxRateService) validate(ctx context.Context, rate *models.TaxRate) error {
+	if rate.Rate.IsNegative() || rate.Rate.GreaterThan(decimal.NewFromInt(100)) {
+		return fmt.Errorf("%w: el porcentaje debe estar entre 0 y 100", ErrInvalidTaxRate)
+	}
+	if rate.ValidTo != nil && rate.ValidTo.Before(rate.ValidFrom) {
+		return fmt.Errorf("%w: la fecha final es anterior a la inicial", ErrInvalidTaxRate)
+	}
+	overlap, err := s.repo.HasOverlap(ctx, rate.Code, rate.ValidFrom, rate.ValidTo, rate.ID)
+	if err != nil {
+		return err
+	}
+	if overlap {
+		return ErrTaxRateOverlap
+	}
+	return nil
+}
+
+func (s *taxRateService) SeedDefaults(ctx context.Context) error {
+	count, err := s.repo.Count(ctx)
+	if err != nil || count > 0 {
+		return err
+	}
+	// Alícuotas vigentes desde el 1 de septiembre de 2018.
+	since := time.Date(2018, time.September, 1, 0, 0, 0, 0, time.UTC)
+	defaults := []models.TaxRate{
+		{Code: models.TaxCodeGeneral, Name: "Alícuota general", Rate: decimal.NewFromInt(16), ValidFrom: since},
+		{Code: models.TaxCodeReduced, Name: "Alícuota reducida", Rate: decimal.NewFromInt(8), ValidFrom: since},
+	}
+	for i := range defaults {
+		if err := s.repo.Create(ctx, &defaults[i]); err != nil {
+			return err
+		}
+	}
+	log.Println("Default tax rates created")
+	return nil
+}
+
+// taxRateResolver busca alícuotas vigentes y recuerda las ya consultadas
+// mientras se calcula una misma orden.
+type taxRateResolver struct {
+	repo  repository.TaxRateRepository
+	date  time.Time
+	cache map[string]decimal.Decimal
+}
+
+func newTaxRateResolver(repo repository.TaxRateRepository, date time.Time) *taxRateResolver {
+	return &taxRateResolver{repo: repo, date: date, cache: map[string]decimal.Decimal{}}
+}
+
+func (r *taxRateResolver) rate(ctx context.Context, code string) (decimal.Decimal, error) {
+	if code == "" {
+		code = models.TaxCodeGeneral
+	}
+	if rate, ok := r.cache[code]; ok {
+		return rate, nil
+	}
+	taxRate, err := r.repo.FindEffective(ctx, code, r.date)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return decimal.Zero, fmt.Errorf("%w: %s al %s", ErrTaxRateNotConfigured, code, r.date.Format("02/01/2006"))
+	}
+	if err != nil {
+		return decimal.Zero, err
+	}
+	r.cache[code] = taxRate.Rate
+	return taxRate.Rate, nil
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/toor/backend/internal/apperror"
+	"github.com/toor/backend/internal/models"
+	"github.com/toor/backend/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// MinPasswordLength es la longitud mínima de las contraseñas.
+const MinPasswordLength = 8
+
+var (
+	// ErrInvalidUser indica datos de usuario inválidos (contraseña corta, rol o funcionario inexistente).
+	ErrInvalidUser = apperror.Validation("INVALID_USER", "usuario inválido")
+	// ErrInvalidRole indica un rol sin código o con permisos desconocidos.
+	ErrInvalidRole = apperror.Validation("INVALID_ROLE", "rol inválido")
+)
+
+// defaultPermissions son todos los permisos del sistema.
+var defaultPermissions = []models.Permission{
+	{Code: models.PermOrdersRead, Description: "Consultar órdenes y sus documentos"},
+	{Code: models.PermOrdersWrite, Description: "Registrar y tramitar órdenes"},
+	{Code: models.PermOrdersApprove, Description: "Aprobar órdenes de la propia unidad"},
+	{Code: models.PermOrdersApproveAll, Description: "Aprobar órdenes de cualquier unidad"},
+	{Code: models.PermProvidersRead, Description: "Consultar proveedores"},
+	{Code: models.PermProvidersWrite, Description: "Registrar y modificar proveedores"},
+	{Code: models.PermMasterDataRead, Description: "Consultar datos maestros"},
+	{Code: models.PermMasterDataWrite, Description: "Modificar datos maestros"},
+	{Code: models.PermAdminRead, Description: "Consultar la configuración del sistema"},
+	{Code: models.PermAdminWrite, Description: "Modificar la configuración, usuarios y roles"},
+}
+
+// defaultRoles son los roles que se crean si no hay roles registrados.
+var defaultRoles = []struct {
+	code, name  string
+	permissions []string
+}{
+	{models.RoleAdmin, "Administrador", nil}, // nil = todos los permisos
+	{models.RoleAnalyst, "Analista de Compras", []string{
+		models.PermOrdersRead, models.PermOrdersWrite,
+		models.PermProvidersRead, models.PermProvidersWrite,
+		models.PermMasterDataRead,
+	}},
+	{models.RoleUnitHead, "Jefe de Unidad", []string{
+		models.PermOrdersRead, models.PermOrdersApprove,
+		models.PermProvidersRead, models.PermMasterDataRead,
+	}},
+	{models.RoleAuditor, "Auditor (solo lectura)", []string{
+		models.PermOrdersRead, models.PermProvidersRead,
+		models.PermMasterDataRead, models.PermAdminRead,
+	}},
+}
+
+type UserService interface {
+	// Roles y permisos
+	GetAllPermissions(ctx context.Context, actor *Actor) ([]models.Permission, error)
+	GetAllRoles(ctx context.Context, actor *Actor) ([]models.Role, error)
+	CreateRole(ctx context.Context, actor *Actor, role *models.Role, permissionCodes []string) (*models.Role, error)
+	UpdateRole(ctx context.Context, actor *Actor, id uint, req *models.Role, permissionCodes []string) (*models.Role, error)
+	DeleteRole(ctx context.Context, actor *Actor, id uint) error
+	// Usuarios
+	GetAllUsers(ctx context.Context, actor *Actor) ([]models.User, error)
+	CreateUser(ctx context.Context, actor *Actor, user *models.User, password string, roleIDs []uint) (*models.User, error)
+	// UpdateUser cambia los datos y roles del usuario; la contraseña solo si no está vacía.
+	UpdateUser(ctx context.Context, actor *Actor, id uint, req *models.User, password string, roleIDs []uint) (*models.User, error)
+
+	// SeedRoles registra los permisos y, si no hay roles, los roles predefinidos.
+	SeedRoles(ctx context.Context) error
+	// SeedAdmin crea el usuario administrador si no hay usuarios registrados.
+	SeedAdmin(ctx context.Context, username, password string) error
+}
+
+type userService struct {
+	repo           repository.UserRepository
+	roleRepo       repository.RoleRepository
+	masterDataRepo repository.MasterDataRepository
+}
+
+func NewUserService(repo repository.UserRepository, roleRepo repository.RoleRepository, masterDataRepo repository.MasterDataRepository) UserService {
+	return &userService{repo: repo, roleRepo: roleRepo, masterDataRepo: masterDataRepo}
+}
+
+// --- Roles y permisos ---
+
+func (s *userService) GetAllPermissions(ctx context.Context, actor *Actor) ([]models.Permission, error) {
+	if err := authorize(actor, models.PermAdminRead); err != nil {
+		return nil, err
+	}
+	return s.roleRepo.GetAllPermissions(ctx)
+}
+
+func (s *userService) GetAllRoles(ctx context.Context, actor *Actor) ([]models.Role, error) {
+	if err := authorize(actor, models.PermAdminRead); err != nil {
+		return nil, err
+	}
+	return s.roleRepo.GetAllRoles(ctx)
+}
+
+func (s *userService) CreateRole(ctx context.Context, actor *Actor, role *models.Role, permissionCodes []string) (*models.Role, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	role.ID = 0
+	if err := s.resolvePermissions(ctx, role, permissionCodes); err != nil {
+		return nil, err
+	}
+	if err := s.roleRepo.CreateRole(ctx, role); err != nil {
+		return nil, err
+	}
+	return s.roleRepo.GetRoleByID(ctx, role.ID)
+}
+
+func (s *userService) UpdateRole(ctx context.Context, actor *Actor, id uint, req *models.Role, permissionCodes []string) (*models.Role, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	role, err := s.roleRepo.GetRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	role.Code = req.Code
+	role.Name = req.Name
+	if err := s.resolvePermissions(ctx, role, permissionCodes); err != nil {
+		return nil, err
+	}
+	if err := s.roleRepo.UpdateRole(ctx, role); err != nil {
+		return nil, err
+	}
+	return s.roleRepo.GetRoleByID(ctx, id)
+}
+
+func (s *userService) DeleteRole(ctx context.Context, actor *Actor, id uint) error {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return err
+	}
+	inUse, err := s.roleRepo.IsRoleInUse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return newInUseError("no se puede eliminar el rol: está asignado a uno o más usuarios")
+	}
+	return s.roleRepo.DeleteRole(ctx, id)
+}
+
+// resolvePermissions valida el rol y reemplaza sus permisos por los de los códigos indicados.
+func (s *userService) resolvePermissions(ctx context.Context, role *models.Role, codes []string) error {
+	role.Code = strings.ToUpper(strings.TrimSpace(role.Code))
+	if role.Code == "" || strings.TrimSpace(role.Name) == "" {
+		return fmt.Errorf("%w: el código y el nombre son obligatorios", ErrInvalidRole)
+	}
+	role.Permissions = []models.Permission{}
+	if len(codes) == 0 {
+		return nil
+	}
+	permissions, err := s.roleRepo.GetPermissionsByCodes(ctx, codes)
+	if err != nil {
+		return err
+	}
+	known := map[string]bool{}
+	for _, p := range permissions {
+		known[p.Code] = true
+	}
+	for _, code := range codes {
+		if !known[code] {
+			return fmt.Errorf("%w: permiso %q desconocido", ErrInvalidRole, code)
+		}
+	}
+	role.Permissions = permissions
+	return nil
+}
+
+// --- Usuarios ---
+
+func (s *userService) GetAllUsers(ctx context.Context, actor *Actor) ([]models.User, error) {
+	if err := authorize(actor, models.PermAdminRead); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAll(ctx)
+}
+
+func (s *userService) CreateUser(ctx context.Context, actor *Actor, user *models.User, password string, roleIDs []uint) (*models.User, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	user.ID = 0
+	user.Username = strings.TrimSpace(user.Username)
+	if user.Username == "" {
+		return nil, fmt.Errorf("%w: el nombre de usuario es obligatorio", ErrInvalidUser)
+	}
+	if err := s.setPassword(user, password); err != nil {
+		return nil, err
+	}
+	if err := s.resolveUserReferences(ctx, user, roleIDs); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, user.ID)
+}
+
+func (s *userService) UpdateUser(ctx context.Context, actor *Actor, id uint, req *models.User, password string, roleIDs []uint) (*models.User, error) {
+	if err := authorize(actor, models.PermAdminWrite); err != nil {
+		return nil, err
+	}
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	user.IsActive = req.IsActive
+	user.OfficialID = req.OfficialID
+	if password != "" {
+		if err := s.setPassword(user, password); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.resolveUserReferences(ctx, user, roleIDs); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *userService) setPassword(user *models.User, password string) error {
+	if len(password) < MinPasswordLength {
+		return fmt.Errorf("%w: la contraseña debe tener al menos %d caracteres", ErrInvalidUser, MinPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = string(hash)
+	return nil
+}
+
+// resolveUserReferences verifica el funcionario y los roles indicados.
+func (s *userService) resolveUserReferences(ctx context.Context, user *models.User, roleIDs []uint) error {
+	user.Official = nil
+	if user.OfficialID != nil {
+		if _, err := s.masterDataRepo.GetOfficialByID(ctx, *user.OfficialID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: el funcionario %d no existe", ErrInvalidUser, *user.OfficialID)
+			}
+			return err
+		}
+	}
+
+	user.Roles = []models.Role{}
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	roles, err := s.roleRepo.GetRolesByIDs(ctx, roleIDs)
+	if err != nil {
+		return err
+	}
+	if len(roles) != len(uniqueIDs(roleIDs)) {
+		return fmt.Errorf("%w: uno o más roles no existen", ErrInvalidUser)
+	}
+	user.Roles = roles
+	return nil
+}
+
+func uniqueIDs(ids []uint) map[uint]bool {
+	set := map[uint]bool{}
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// --- Datos iniciales ---
+
+func (s *userService) SeedRoles(ctx context.Context) error {
+	if err := s.roleRepo.EnsurePermissions(ctx, defaultPermissions); err != nil {
+		return err
+	}
+	count, err := s.roleRepo.CountRoles(ctx)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	all := make([]string, 0, len(defaultPermissions))
+	for _, p := range defaultPermissions {
+		all = append(all, p.Code)
+	}
+	for _, def := range defaultRoles {
+		codes := def.permissions
+		if codes == nil {
+			codes = all
+		}
+		role := &models.Role{Code: def.code, Name: def.name}
+		if err := s.resolvePermissions(ctx, role, codes); err != nil {
+			return err
+		}
+		if err := s.roleRepo.CreateRole(ctx, role); err != nil {
+			return err
+		}
+	}
+	log.Println("Default roles created")
+
+	// Los usuarios creados antes de existir los roles (el administrador
+	// inicial) conservan el acceso total que tenían.
+	users, err := s.repo.GetWithoutRoles(ctx)
+	if err != nil {
+		return err
+	}
+	admin, err := s.roleRepo.GetRoleByCode(ctx, models.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	for i := range users {
+		users[i].Roles = []models.Role{*admin}
+		if err := s.repo.Update(ctx, &users[i]); err != nil {
+			return err
+		}
+		log.Printf("Role %s assigned to existing user %q", models.RoleAdmin, users[i].Username)
+	}
+	return nil
+}
+
+func (s *userService) SeedAdmin(ctx context.Context, username, password string) error {
+	count, err := s.repo.Count(ctx)
+	if err != nil || count > 0 {
+		return err
+	}
+	if username == "" || password == "" {
+		log.Println("No users registered; set ADMIN_USERNAME and ADMIN_PASSWORD to create the first one")
+		return nil
+	}
+	admin, err := s.roleRepo.GetRoleByCode(ctx, models.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("could not find the %s role: %w", models.RoleAdmin, err)
+	}
+	user := &models.User{Username: username, IsActive: true, Roles: []models.Role{*admin}}
+	if err := s.setPassword(user, password); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, user); err != nil {
+		return err
+	}
+	log.Printf("Admin user %q created", username)
+	return nil
+}
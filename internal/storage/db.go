@@ -3,6 +3,7 @@ package storage
 import (
 	"log"
 
+	"github.com/toor/backend/internal/audit"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -12,5 +13,8 @@ func MustInit(dsn string) *gorm.DB {
 	if err != nil {
 		log.Fatalf("failed to connect to DB: %v", err)
 	}
+	if err := audit.Register(db); err != nil {
+		log.Fatalf("failed to register audit callbacks: %v", err)
+	}
 	return db
 }
\ No newline at end of file